@@ -0,0 +1,46 @@
+package viterbi
+
+// ambiguousSteps marks observations where state of the path has been chosen over runner-up state within ambiguity epsilon.
+// Final holds probabilities of the last trellis column combined with end probabilities.
+// Path is expected to be evaluated already, so probabilities are known to be valid
+func (v *Viterbi) ambiguousSteps(V []map[State]ViterbiVal, final map[State]float64, path []State, logSpace bool) []bool {
+	ambiguous := make([]bool, len(path))
+	last := len(path) - 1
+	for st, prob := range final {
+		if st != path[last] && v.withinAmbiguity(logSpace, final[path[last]], prob) {
+			ambiguous[last] = true
+			break
+		}
+	}
+	for t := last - 1; t >= 0; t-- {
+		next := path[t+1]
+		transitionProb, _, _ := v.transitionProbability(path[t], next, logSpace)
+		best := combine(logSpace, V[t][path[t]].prob, transitionProb)
+		for _, r := range v.predecessorsOf(next) {
+			stateProb, ok := V[t][r]
+			if !ok || r == path[t] {
+				continue
+			}
+			transitionProb, ok, _ := v.transitionProbability(r, next, logSpace)
+			if !ok {
+				continue
+			}
+			if v.withinAmbiguity(logSpace, best, combine(logSpace, stateProb.prob, transitionProb)) {
+				ambiguous[t] = true
+				break
+			}
+		}
+	}
+	return ambiguous
+}
+
+// withinAmbiguity reports whether runner-up probability is within ambiguity epsilon of the best one
+func (m *Model) withinAmbiguity(logSpace bool, best, runnerUp float64) bool {
+	if impossible(logSpace, runnerUp) {
+		return false
+	}
+	if logSpace {
+		return best-runnerUp <= *m.ambiguityEpsilon
+	}
+	return best-runnerUp <= *m.ambiguityEpsilon*best
+}
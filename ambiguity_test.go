@@ -0,0 +1,69 @@
+package viterbi
+
+import (
+	"math"
+	"testing"
+)
+
+func TestViterbiAmbiguity(t *testing.T) {
+	var (
+		incStates = []CustomState{
+			CustomState{Name: "a", id: 1},
+			CustomState{Name: "b", id: 2},
+		}
+		observations = []CustomObservation{
+			CustomObservation{Name: "o1", id: 1},
+			CustomObservation{Name: "o2", id: 2},
+		}
+	)
+	v := New()
+	for i := range incStates {
+		v.AddState(incStates[i])
+	}
+	for i := range observations {
+		v.AddObservation(observations[i])
+	}
+	// Both states are equally probable for the first observation, 'a' is clearly better for the second one
+	for _, from := range incStates {
+		v.PutStartProbability(from, 0.5)
+		v.PutEmissionProbability(from, observations[0], 0.5)
+		for _, to := range incStates {
+			v.PutTransitionProbability(from, to, 0.5)
+		}
+	}
+	v.PutEmissionProbability(incStates[0], observations[1], 0.9)
+	v.PutEmissionProbability(incStates[1], observations[1], 0.1)
+
+	vpath, err := v.EvalPath()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if vpath.Ambiguous != nil {
+		t.Error(
+			"Ambiguity has not to be evaluated by default, but got", vpath.Ambiguous,
+		)
+	}
+
+	v.SetAmbiguityEpsilon(0.1)
+	vpath, err = v.EvalPath()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(vpath.Ambiguous) != 2 || !vpath.Ambiguous[0] || vpath.Ambiguous[1] {
+		t.Error(
+			"Ambiguous has to be [true false], but got", vpath.Ambiguous,
+		)
+	}
+
+	logV := v.Clone()
+	logV.SetAmbiguityEpsilon(math.Log(9) + 1e-9)
+	logPath, err := logV.ToLog().EvalPathLogProbabilities()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(logPath.Ambiguous) != 2 || !logPath.Ambiguous[0] || !logPath.Ambiguous[1] {
+		t.Error(
+			"Ambiguous has to be [true true] for log(9) epsilon, but got", logPath.Ambiguous,
+		)
+	}
+}
@@ -0,0 +1,96 @@
+package viterbi
+
+// Backward evaluates backward variables β for every observation: β[t][s] is probability of observations after t given state s at t
+// (end probability included, so β of the last observation is end probability of the state).
+// https://en.wikipedia.org/wiki/Forward%E2%80%93backward_algorithm#Backward_probabilities
+// When every probability is in [0;1]
+func (v *Viterbi) Backward() ([]map[State]float64, error) {
+	return v.backward(false)
+}
+
+// BackwardLog is the same as Backward, but when every probability is logarithmic.
+// Output can be combined with ForwardLog without underflow
+func (v *Viterbi) BackwardLog() ([]map[State]float64, error) {
+	return v.backward(true)
+}
+
+func (v *Viterbi) backward(logSpace bool) ([]map[State]float64, error) {
+	return v.backwardFrom(logSpace, 0)
+}
+
+// backwardFrom is the same as backward, but evaluates backward variables of observations from first one only (the rest are nil)
+func (v *Viterbi) backwardFrom(logSpace bool, first int) ([]map[State]float64, error) {
+	if err := v.validate(); err != nil {
+		return nil, err
+	}
+
+	last := len(v.observations) - 1
+	beta := make([]map[State]float64, len(v.observations))
+	beta[last] = make(map[State]float64)
+	for _, st := range v.states {
+		endProb, err := v.endProbability(st, logSpace)
+		if err != nil {
+			return nil, err
+		}
+		if impossible(logSpace, endProb) {
+			continue
+		}
+		beta[last][st] = endProb
+	}
+	if len(beta[last]) == 0 {
+		return nil, ErrNoValidPath
+	}
+
+	for t := last - 1; t >= first; t-- {
+		beta[t] = make(map[State]float64)
+		unknown := v.unknownAt(t + 1)
+		for _, s := range v.states {
+			outgoing := []float64{}
+			for _, r := range v.states {
+				nextProb, ok := beta[t+1][r]
+				if !ok || !v.isPredecessor(s, r) {
+					continue
+				}
+				emissionProb, ok, err := v.emissionProbability(r, t+1, unknown, logSpace)
+				if err != nil {
+					return nil, err
+				}
+				if !ok {
+					// No emission for next state of next observation
+					continue
+				}
+				transitionProb, ok, err := v.transitionProbability(s, r, logSpace)
+				if err != nil {
+					return nil, err
+				}
+				if !ok {
+					// No transition between states
+					continue
+				}
+				outgoing = append(outgoing, combine(logSpace, combine(logSpace, transitionProb, emissionProb), nextProb))
+			}
+			if len(outgoing) == 0 {
+				// Observations after t can't be produced starting from the state
+				continue
+			}
+			prob := sumProbabilities(logSpace, outgoing...)
+			if impossible(logSpace, prob) {
+				continue
+			}
+			beta[t][s] = prob
+		}
+		if len(beta[t]) == 0 {
+			// None of states at t leads to the next observation
+			return nil, v.pathBroken(t + 1)
+		}
+	}
+	return beta, nil
+}
+
+// one returns probability of certain event: 1 for classic probabilities and 0 for logarithmic ones
+func one(logSpace bool) float64 {
+	if logSpace {
+		return 0
+	}
+	return 1
+}
@@ -0,0 +1,84 @@
+package viterbi
+
+import (
+	"math"
+	"testing"
+)
+
+func TestViterbiBackward(t *testing.T) {
+	v, incStates, incomingObservations := healthModel()
+
+	beta, err := v.Backward()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(beta) != len(incomingObservations) {
+		t.Fatal(
+			"Expected", len(incomingObservations), "time steps, but got:", len(beta),
+		)
+	}
+	for _, st := range incStates {
+		if beta[2][st] != 1 {
+			t.Error(
+				"Backward probability for the last observation has to be 1, but got", beta[2][st],
+			)
+		}
+	}
+
+	// Total probability evaluated backward has to match forward algorithm
+	forward, err := v.Forward()
+	if err != nil {
+		t.Fatal(err)
+	}
+	total := 0.0
+	for _, st := range incStates {
+		startProb, _ := v.GetStartProbability(st)
+		emissionProb, _ := v.GetEmissionProbability(st, incomingObservations[0])
+		total += startProb * emissionProb * beta[0][st]
+	}
+	if math.Abs(total-forward) > 1e-12 {
+		t.Error(
+			"Total probability has to be", forward, "but got", total,
+		)
+	}
+
+	logV := logModel(v)
+	logBeta, err := logV.BackwardLog()
+	if err != nil {
+		t.Fatal(err)
+	}
+	for step := range beta {
+		for _, st := range incStates {
+			if math.Abs(logBeta[step][st]-math.Log(beta[step][st])) > 1e-12 {
+				t.Error(
+					"Logarithmic backward probability at", step, "for", st, "has to be", math.Log(beta[step][st]), "but got", logBeta[step][st],
+				)
+			}
+		}
+	}
+}
+
+func TestViterbiBackwardPredecessors(t *testing.T) {
+	v, incStates, incomingObservations := healthModel()
+	v.SetPredecessors(incStates[0], []State{incStates[1]})
+
+	beta, err := v.Backward()
+	if err != nil {
+		t.Fatal(err)
+	}
+	forward, err := v.Forward()
+	if err != nil {
+		t.Fatal(err)
+	}
+	total := 0.0
+	for _, st := range incStates {
+		startProb, _ := v.GetStartProbability(st)
+		emissionProb, _ := v.GetEmissionProbability(st, incomingObservations[0])
+		total += startProb * emissionProb * beta[0][st]
+	}
+	if math.Abs(total-forward) > 1e-12 {
+		t.Error(
+			"Total probability has to be", forward, "but got", total,
+		)
+	}
+}
@@ -0,0 +1,92 @@
+package viterbi
+
+import (
+	"math"
+	"sort"
+)
+
+// pruneColumn removes the least probable states from trellis column according to prune ratio and beam width.
+// Equal probabilities are resolved according to the tie-breaker (see SetTieBreaker). It returns removed states
+func (v *Viterbi) pruneColumn(column map[State]ViterbiVal, logSpace bool) []State {
+	var removed []State
+	if v.pruneRatio > 0 && len(column) > 1 {
+		maxProb := math.Inf(-1)
+		for _, value := range column {
+			maxProb = math.Max(maxProb, value.prob)
+		}
+		threshold := v.pruneThreshold(logSpace, maxProb)
+		for _, st := range v.states {
+			if value, ok := column[st]; ok && value.prob < threshold {
+				delete(column, st)
+				removed = append(removed, st)
+			}
+		}
+	}
+	if v.beamWidth <= 0 || len(column) <= v.beamWidth {
+		return removed
+	}
+	states := make([]State, 0, len(column))
+	for _, st := range v.states {
+		if _, ok := column[st]; ok {
+			states = append(states, st)
+		}
+	}
+	sort.Slice(states, func(i, j int) bool {
+		return v.preferState(column[states[i]].prob, states[i], column[states[j]].prob, states[j])
+	})
+	for _, st := range states[v.beamWidth:] {
+		delete(column, st)
+	}
+	return append(removed, states[v.beamWidth:]...)
+}
+
+// pruneThreshold returns probability below which states are pruned according to prune ratio given the best probability of column
+func (m *Model) pruneThreshold(logSpace bool, maxProb float64) float64 {
+	if logSpace {
+		return maxProb + math.Log(m.pruneRatio)
+	}
+	return maxProb * m.pruneRatio
+}
+
+// pruneCompactStep is the same as pruneColumn, but for compact back-pointers of EvalPathCompact
+func (v *Viterbi) pruneCompactStep(step compactStep, column denseColumn, logSpace bool) compactStep {
+	if v.pruneRatio > 0 && len(step.states) > 1 {
+		maxProb := math.Inf(-1)
+		for _, i := range step.states {
+			maxProb = math.Max(maxProb, column.values[i].prob)
+		}
+		threshold := v.pruneThreshold(logSpace, maxProb)
+		kept := compactStep{states: make([]int32, 0, len(step.states))}
+		for k, i := range step.states {
+			if column.values[i].prob < threshold {
+				continue
+			}
+			kept.states = append(kept.states, i)
+			if step.prev != nil {
+				kept.prev = append(kept.prev, step.prev[k])
+			}
+		}
+		step = kept
+	}
+	if v.beamWidth <= 0 || len(step.states) <= v.beamWidth {
+		return step
+	}
+	order := make([]int, len(step.states))
+	for i := range order {
+		order[i] = i
+	}
+	sort.Slice(order, func(i, j int) bool {
+		a, b := step.states[order[i]], step.states[order[j]]
+		return v.preferState(column.values[a].prob, v.states[a], column.values[b].prob, v.states[b])
+	})
+	keep := order[:v.beamWidth]
+	sort.Ints(keep)
+	pruned := compactStep{states: make([]int32, 0, len(keep))}
+	for _, i := range keep {
+		pruned.states = append(pruned.states, step.states[i])
+		if step.prev != nil {
+			pruned.prev = append(pruned.prev, step.prev[i])
+		}
+	}
+	return pruned
+}
@@ -0,0 +1,135 @@
+package viterbi
+
+import (
+	"testing"
+)
+
+func TestViterbiBeamWidth(t *testing.T) {
+	v := randomModel(20, 15, 3)
+	exact, err := v.EvalPath()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Beam which is wider than state space reproduces exact evaluation
+	v.SetBeamWidth(20)
+	vpath, err := v.EvalPath()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if vpath.Probability != exact.Probability {
+		t.Error(
+			"Probability has to be", exact.Probability, "but got", vpath.Probability,
+		)
+	}
+
+	v.SetBeamWidth(3)
+	vpath, trellis, err := v.EvalPathWithTrellis()
+	if err != nil {
+		t.Fatal(err)
+	}
+	for step := range trellis {
+		if len(trellis[step]) > 3 {
+			t.Error(
+				"Expected at most 3 states at", step, "but got:", len(trellis[step]),
+			)
+		}
+	}
+	if vpath.Probability > exact.Probability {
+		t.Error(
+			"Probability of pruned decode can't exceed exact one:", vpath.Probability, ">", exact.Probability,
+		)
+	}
+	compact, err := v.EvalPathCompact()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if compact.Probability != vpath.Probability {
+		t.Error(
+			"Compact evaluation has to prune the same way:", compact.Probability, "!=", vpath.Probability,
+		)
+	}
+	for i := range vpath.Path {
+		if compact.Path[i] != vpath.Path[i] {
+			t.Error(
+				"State", i, "has to be", vpath.Path[i], "but got", compact.Path[i],
+			)
+		}
+	}
+
+	v.SetBeamWidth(0)
+	vpath, err = v.EvalPath()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if vpath.Probability != exact.Probability {
+		t.Error(
+			"Probability has to be", exact.Probability, "but got", vpath.Probability,
+		)
+	}
+}
+
+func TestViterbiPruneRatio(t *testing.T) {
+	v := randomModel(20, 15, 3)
+	exact, err := v.EvalPath()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	v.SetPruneRatio(0.5)
+	vpath, trellis, err := v.EvalPathWithTrellis()
+	if err != nil {
+		t.Fatal(err)
+	}
+	for step := range trellis {
+		maxProb := 0.0
+		for _, cell := range trellis[step] {
+			if cell.Probability > maxProb {
+				maxProb = cell.Probability
+			}
+		}
+		for _, cell := range trellis[step] {
+			if cell.Probability < 0.5*maxProb {
+				t.Error(
+					"State", cell.State, "at", step, "has to be pruned:", cell.Probability, "<", 0.5*maxProb,
+				)
+			}
+		}
+	}
+	if vpath.Probability > exact.Probability {
+		t.Error(
+			"Probability of pruned decode can't exceed exact one:", vpath.Probability, ">", exact.Probability,
+		)
+	}
+	compact, err := v.EvalPathCompact()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if compact.Probability != vpath.Probability {
+		t.Error(
+			"Compact evaluation has to prune the same way:", compact.Probability, "!=", vpath.Probability,
+		)
+	}
+	logPath, err := v.ToLog().EvalPathLogProbabilities()
+	if err != nil {
+		t.Fatal(err)
+	}
+	for i := range vpath.Path {
+		if logPath.Path[i] != vpath.Path[i] {
+			t.Error(
+				"State", i, "of logarithmic evaluation has to be", vpath.Path[i], "but got", logPath.Path[i],
+			)
+		}
+	}
+
+	v.SetPruneRatio(0)
+	vpath, err = v.EvalPath()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if vpath.Probability != exact.Probability {
+		t.Error(
+			"Probability has to be", exact.Probability, "but got", vpath.Probability,
+		)
+	}
+}
@@ -0,0 +1,45 @@
+package viterbi
+
+import (
+	"fmt"
+)
+
+// PathBrokenError describes observation for which no state could be reached. It matches ErrPathBroken via errors.Is
+type PathBrokenError struct {
+	// ObservationIndex is position of the observation in the sequence
+	ObservationIndex int
+	Observation      Observation
+	// UnreachableStates are states which could emit the observation, but have no valid start (for the first observation) or incoming transition
+	UnreachableStates []State
+}
+
+func (e *PathBrokenError) Error() string {
+	return fmt.Sprintf("%s %d (%v): %d states could emit it, but none has been reached", ErrPathBroken, e.ObservationIndex, e.Observation, len(e.UnreachableStates))
+}
+
+// Unwrap returns ErrPathBroken, so errors.Is(err, ErrPathBroken) holds
+func (e *PathBrokenError) Unwrap() error {
+	return ErrPathBroken
+}
+
+// Is reports whether target is ErrNoValidPath: broken path means there is no valid path at all
+func (e *PathBrokenError) Is(target error) bool {
+	return target == ErrNoValidPath
+}
+
+// pathBroken returns *PathBrokenError for observation t
+func (v *Viterbi) pathBroken(t int) error {
+	unreachable := []State{}
+	unknown := v.unknownAt(t)
+	for _, s := range v.states {
+		// Logarithmic mode does not check range: only presence of emission matters here
+		if _, ok, _ := v.emissionProbability(s, t, unknown, true); ok {
+			unreachable = append(unreachable, s)
+		}
+	}
+	return &PathBrokenError{
+		ObservationIndex:  t,
+		Observation:       v.observations[t],
+		UnreachableStates: unreachable,
+	}
+}
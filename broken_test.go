@@ -0,0 +1,73 @@
+package viterbi
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestViterbiPathBrokenError(t *testing.T) {
+	v, incStates, incomingObservations := healthModel()
+	unknown := CustomObservation{Name: "unknown", id: 4}
+	v.AddObservation(unknown)
+	// 'Fever' could emit unknown observation, but nothing leads to 'Fever'
+	v.PutEmissionProbability(incStates[1], unknown, 1.0)
+	delete(v.transitionProbabilities, TransitionHash{incStates[0].ID(), incStates[1].ID()})
+	delete(v.transitionProbabilities, TransitionHash{incStates[1].ID(), incStates[1].ID()})
+
+	_, err := v.EvalPath()
+	if !errors.Is(err, ErrPathBroken) {
+		t.Fatal(
+			"Error has to be ErrPathBroken, but got", err,
+		)
+	}
+	var brokenErr *PathBrokenError
+	if !errors.As(err, &brokenErr) {
+		t.Fatal(
+			"Error has to be *PathBrokenError, but got", err,
+		)
+	}
+	if brokenErr.ObservationIndex != len(incomingObservations) {
+		t.Error(
+			"Observation index has to be", len(incomingObservations), ", but got", brokenErr.ObservationIndex,
+		)
+	}
+	if brokenErr.Observation.ID() != unknown.ID() {
+		t.Error(
+			"Observation has to be", unknown, ", but got", brokenErr.Observation,
+		)
+	}
+	if len(brokenErr.UnreachableStates) != 1 || brokenErr.UnreachableStates[0].ID() != incStates[1].ID() {
+		t.Error(
+			"Unreachable states have to be [Fever], but got", brokenErr.UnreachableStates,
+		)
+	}
+}
+
+func TestViterbiPathBrokenErrorBackward(t *testing.T) {
+	v, incStates, incomingObservations := healthModel()
+	unknown := CustomObservation{Name: "unknown", id: 4}
+	v.AddObservation(unknown)
+	v.PutEmissionProbability(incStates[1], unknown, 1.0)
+	delete(v.transitionProbabilities, TransitionHash{incStates[0].ID(), incStates[1].ID()})
+	delete(v.transitionProbabilities, TransitionHash{incStates[1].ID(), incStates[1].ID()})
+
+	_, err := v.Backward()
+	var brokenErr *PathBrokenError
+	if !errors.As(err, &brokenErr) {
+		t.Fatal(
+			"Error has to be *PathBrokenError, but got", err,
+		)
+	}
+	if brokenErr.ObservationIndex != len(incomingObservations) {
+		t.Error(
+			"Observation index has to be", len(incomingObservations), ", but got", brokenErr.ObservationIndex,
+		)
+	}
+
+	_, err = v.EvalPosterior()
+	if !errors.As(err, &brokenErr) {
+		t.Fatal(
+			"Error has to be *PathBrokenError, but got", err,
+		)
+	}
+}
@@ -0,0 +1,96 @@
+package viterbi
+
+import (
+	"errors"
+	"fmt"
+)
+
+// Builder constructs model by chained calls, e.g. NewBuilder().States(a, b).Observations(o1, o2).Start(a, 1).Build().
+// Problems found while building (nil or duplicate states, nil observations) are collected and returned by Build
+type Builder struct {
+	v        *Viterbi
+	problems []error
+}
+
+// NewBuilder returns builder of empty model
+func NewBuilder() *Builder {
+	return &Builder{v: New()}
+}
+
+// States adds states to the model (see AddStateChecked)
+func (b *Builder) States(states ...State) *Builder {
+	for _, st := range states {
+		if err := b.v.AddStateChecked(st); err != nil {
+			b.problems = append(b.problems, err)
+		}
+	}
+	return b
+}
+
+// Observations appends observations to the sequence to be decoded (see AddObservation)
+func (b *Builder) Observations(observations ...Observation) *Builder {
+	for _, obs := range observations {
+		if isNil(obs) {
+			b.problems = append(b.problems, ErrNilObservation)
+			continue
+		}
+		b.v.AddObservation(obs)
+	}
+	return b
+}
+
+// Start puts start probability of the state (see PutStartProbability)
+func (b *Builder) Start(s State, val float64) *Builder {
+	if b.checkStates("Start", s) {
+		b.v.PutStartProbability(s, val)
+	}
+	return b
+}
+
+// Emission puts probability of the state to emit observation (see PutEmissionProbability)
+func (b *Builder) Emission(s State, obs Observation, val float64) *Builder {
+	if !b.checkStates("Emission", s) {
+		return b
+	}
+	if isNil(obs) {
+		b.problems = append(b.problems, fmt.Errorf("%w: passed to Emission", ErrNilObservation))
+		return b
+	}
+	b.v.PutEmissionProbability(s, obs, val)
+	return b
+}
+
+// Transition puts probability of transition between states (see PutTransitionProbability)
+func (b *Builder) Transition(from, to State, val float64) *Builder {
+	if b.checkStates("Transition", from, to) {
+		b.v.PutTransitionProbability(from, to, val)
+	}
+	return b
+}
+
+// Build validates constructed model (see ValidateModel) and returns it.
+// Every problem found while building and validating is returned in single *ValidationError
+// When every probability is in [0;1]
+func (b *Builder) Build() (*Viterbi, error) {
+	problems := append([]error{}, b.problems...)
+	var validationErr *ValidationError
+	if errors.As(b.v.ValidateModel(), &validationErr) {
+		problems = append(problems, validationErr.Problems...)
+	}
+	if len(problems) != 0 {
+		return nil, &ValidationError{Problems: problems}
+	}
+	return b.v, nil
+}
+
+// checkStates records problem for every nil state passed to method. It returns false when there is any
+func (b *Builder) checkStates(method string, states ...State) bool {
+	ok := true
+	for _, st := range states {
+		if isNil(st) {
+			b.problems = append(b.problems, fmt.Errorf("%w: passed to %s", ErrNilState, method))
+			ok = false
+		}
+	}
+	return ok
+}
@@ -0,0 +1,59 @@
+package viterbi
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestViterbiBuilder(t *testing.T) {
+	_, incStates, incomingObservations := healthModel()
+	healthy, fever := incStates[0], incStates[1]
+	normal, cold, dizzy := incomingObservations[0], incomingObservations[1], incomingObservations[2]
+	v, err := NewBuilder().
+		States(healthy, fever).
+		Observations(normal, cold, dizzy).
+		Start(healthy, 0.6).Start(fever, 0.4).
+		Emission(healthy, normal, 0.5).Emission(healthy, cold, 0.4).Emission(healthy, dizzy, 0.1).
+		Emission(fever, normal, 0.1).Emission(fever, cold, 0.3).Emission(fever, dizzy, 0.6).
+		Transition(healthy, healthy, 0.7).Transition(healthy, fever, 0.3).
+		Transition(fever, healthy, 0.4).Transition(fever, fever, 0.6).
+		Build()
+	if err != nil {
+		t.Fatal(err)
+	}
+	vpath, err := v.EvalPath()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if vpath.String() != "0.01512: [1 1 2]" {
+		t.Error(
+			"Path has to be '0.01512: [1 1 2]', but got", vpath,
+		)
+	}
+
+	_, err = NewBuilder().
+		States(healthy, healthy, nil).
+		Observations(normal).
+		Start(healthy, 1).
+		Emission(healthy, normal, 1.5).
+		Transition(healthy, nil, 1).
+		Build()
+	var validationErr *ValidationError
+	if !errors.As(err, &validationErr) {
+		t.Fatal(
+			"Error has to be *ValidationError, but got", err,
+		)
+	}
+	if len(validationErr.Problems) != 4 {
+		t.Error(
+			"Expected 4 problems, but got:", validationErr.Problems,
+		)
+	}
+	for _, target := range []error{ErrDuplicateState, ErrNilState, ErrInvalidProbability} {
+		if !errors.Is(err, target) {
+			t.Error(
+				"Error has to contain", target, "but got", err,
+			)
+		}
+	}
+}
@@ -0,0 +1,13 @@
+package viterbi
+
+// Clone returns deep copy of the model: states, observations, every probability and setting are copied,
+// so changing the copy (e.g. via Normalize or Train) does not affect the original. States and observations themselves are shared.
+// Online evaluation started by Begin is not copied
+func (v *Viterbi) Clone() *Viterbi {
+	copied := v.withProbabilities(func(val float64) float64 {
+		return val
+	})
+	copied.emissionFunc = v.emissionFunc
+	copied.transitionFunc = v.transitionFunc
+	return copied
+}
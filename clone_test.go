@@ -0,0 +1,96 @@
+package viterbi
+
+import (
+	"testing"
+)
+
+func TestViterbiClone(t *testing.T) {
+	v, incStates, incomingObservations := healthModel()
+	v.PutEndProbability(incStates[1], 0.5)
+	v.SetPredecessors(incStates[1], []State{incStates[0], incStates[1]})
+	correctPath, err := v.EvalPath()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	copied := v.Clone()
+	copiedPath, err := copied.EvalPath()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if copiedPath.Probability != correctPath.Probability {
+		t.Error(
+			"Probability of the copy has to be", correctPath.Probability, ", but got", copiedPath.Probability,
+		)
+	}
+
+	// Changing the copy has not to affect the original
+	copied.AddState(CustomState{Name: "Dead", id: 3})
+	copied.AddObservation(incomingObservations[0])
+	copied.AddStartProbability(incStates[0], 0.1)
+	copied.AddEmissionProbability(incStates[0], incomingObservations[0], 0.1)
+	copied.AddTransitionProbability(incStates[0], incStates[0], 0.1)
+	copied.PutEndProbability(incStates[0], 0.5)
+	copied.SetPredecessors(incStates[1], []State{incStates[1]})
+	copied.AddConstraint(0, []State{incStates[1]})
+	if err := copied.Normalize(); err != nil {
+		t.Fatal(err)
+	}
+
+	if len(v.states) != 2 || len(v.observations) != 3 || len(v.constraints) != 0 || len(v.predecessors[incStates[1].ID()]) != 2 {
+		t.Error(
+			"States, observations, constraints and predecessors of the original have to stay untouched",
+		)
+	}
+	if val, _ := v.GetStartProbability(incStates[0]); val != 0.6 {
+		t.Error(
+			"Start probability of the original has to stay 0.6, but got", val,
+		)
+	}
+	if _, ok := v.GetEndProbability(incStates[0]); ok {
+		t.Error(
+			"End probability of the original has not to be set",
+		)
+	}
+	vpath, err := v.EvalPath()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if vpath.Probability != correctPath.Probability {
+		t.Error(
+			"Probability of the original has to be", correctPath.Probability, ", but got", vpath.Probability,
+		)
+	}
+}
+
+func TestViterbiCloneCounts(t *testing.T) {
+	v, incStates, incomingObservations := healthModel()
+	v.IncStart(incStates[0])
+	v.IncEmission(incStates[0], incomingObservations[0])
+	v.IncTransition(incStates[0], incStates[1])
+
+	copied := v.Clone()
+	copied.IncStart(incStates[0])
+	copied.IncStart(incStates[0])
+	copied.IncEmission(incStates[0], incomingObservations[0])
+	copied.IncTransition(incStates[0], incStates[1])
+	if err := copied.Finalize(0); err != nil {
+		t.Fatal(err)
+	}
+
+	if v.startCounts[incStates[0].ID()] != 1 {
+		t.Error(
+			"Start count of the original has to stay 1, but got", v.startCounts[incStates[0].ID()],
+		)
+	}
+	if v.emissionCounts[EmissionHash{incStates[0].ID(), incomingObservations[0].ID()}] != 1 {
+		t.Error(
+			"Emission count of the original has to stay 1, but got", v.emissionCounts[EmissionHash{incStates[0].ID(), incomingObservations[0].ID()}],
+		)
+	}
+	if v.transitionCounts[TransitionHash{incStates[0].ID(), incStates[1].ID()}] != 1 {
+		t.Error(
+			"Transition count of the original has to stay 1, but got", v.transitionCounts[TransitionHash{incStates[0].ID(), incStates[1].ID()}],
+		)
+	}
+}
@@ -0,0 +1,134 @@
+package viterbi
+
+import (
+	"math"
+	"sort"
+)
+
+// compactStep keeps back-pointers of single observation: for every reachable state (index in states slice, ascending)
+// index of previous state on the most probable path
+type compactStep struct {
+	states []int32
+	prev   []int32
+}
+
+// EvalPathCompact is the same as EvalPath, but it does not keep whole trellis in memory.
+// Only current and previous columns are kept alongside compact back-pointers (state indices per observation),
+// so memory consumption is O(T·activeStates) of integers
+// When every probability is in [0;1]
+func (v *Viterbi) EvalPathCompact() (ViterbiPath, error) {
+	return v.evalPathCompact(false)
+}
+
+// EvalPathLogProbabilitiesCompact is the same as EvalPathCompact, but when every probability is logarithmic
+func (v *Viterbi) EvalPathLogProbabilitiesCompact() (ViterbiPath, error) {
+	return v.evalPathCompact(true)
+}
+
+func (v *Viterbi) evalPathCompact(logSpace bool) (ViterbiPath, error) {
+	if err := v.validate(); err != nil {
+		return ViterbiPath{}, err
+	}
+
+	opts := evalOptions{logSpace: logSpace, index: v.stateIndex()}
+	prev, column := newDenseColumn(len(v.states)), newDenseColumn(len(v.states))
+	steps := make([]compactStep, len(v.observations))
+	unknown := v.unknownAt(0)
+	for i, st := range v.states {
+		prob, ok, err := v.initialProbability(st, unknown, logSpace)
+		if err != nil {
+			return ViterbiPath{}, err
+		}
+		if !ok {
+			continue
+		}
+		column.values[i], column.set[i] = ViterbiVal{prob: prob}, true
+		steps[0].states = append(steps[0].states, int32(i))
+	}
+	if len(steps[0].states) == 0 {
+		return ViterbiPath{}, v.pathBroken(0)
+	}
+	logScale := 0.0
+	var err error
+	if steps[0], logScale, err = v.finishCompactStep(steps[0], column, 0, logSpace, logScale); err != nil {
+		return ViterbiPath{}, err
+	}
+
+	for t := 1; t < len(v.observations); t++ {
+		prev, column = column, prev
+		column.reset()
+		unknown := v.unknownAt(t)
+		for i, s := range v.states {
+			value, ok, err := v.evalCell(prev, s, t, unknown, opts)
+			if err != nil {
+				return ViterbiPath{}, err
+			}
+			if !ok {
+				continue
+			}
+			column.values[i], column.set[i] = value, true
+			steps[t].states = append(steps[t].states, int32(i))
+			steps[t].prev = append(steps[t].prev, int32(opts.index[value.prev.ID()]))
+		}
+		if len(steps[t].states) == 0 {
+			return ViterbiPath{}, v.pathBroken(t)
+		}
+		if steps[t], logScale, err = v.finishCompactStep(steps[t], column, t, logSpace, logScale); err != nil {
+			return ViterbiPath{}, err
+		}
+	}
+
+	last := steps[len(steps)-1].states
+	final := make(map[int32]float64, len(last))
+	best := int32(-1)
+	for _, i := range last {
+		endProb, err := v.endProbability(v.states[i], logSpace)
+		if err != nil {
+			return ViterbiPath{}, err
+		}
+		final[i] = combine(logSpace, column.values[i].prob, endProb)
+		if impossible(logSpace, final[i]) {
+			continue
+		}
+		if best < 0 || v.preferState(final[i], v.states[i], final[best], v.states[best]) {
+			best = i
+		}
+	}
+	if best < 0 {
+		return ViterbiPath{}, ErrNoValidPath
+	}
+
+	path := ViterbiPath{Probability: final[best], LogProbability: logProbability(logSpace, final[best]), Path: make([]State, len(v.observations))}
+	if !logSpace {
+		path.Probability *= math.Exp(logScale)
+		path.LogProbability += logScale
+	}
+	idx := best
+	for t := len(steps) - 1; t >= 0; t-- {
+		path.Path[t] = v.states[idx]
+		if t == 0 {
+			break
+		}
+		states := steps[t].states
+		pos := sort.Search(len(states), func(k int) bool { return states[k] >= idx })
+		idx = steps[t].prev[pos]
+	}
+	path.StepProbabilities = v.stepProbabilities(path.Path, logSpace)
+	return path, nil
+}
+
+// finishCompactStep prunes evaluated column t (unsetting cells of pruned states), rescales classic probabilities and checks minimum probability
+// like evalTrellis does. It returns pruned step and accumulated logarithm of scaling factor
+func (v *Viterbi) finishCompactStep(step compactStep, column denseColumn, t int, logSpace bool, logScale float64) (compactStep, float64, error) {
+	pruned := v.pruneCompactStep(step, column, logSpace)
+	if len(pruned.states) != len(step.states) {
+		column.reset()
+		for _, i := range pruned.states {
+			column.set[i] = true
+		}
+	}
+	if !logSpace {
+		logScale += rescaleDense(column)
+	}
+	return pruned, logScale, v.checkDenseMinProbability(column, t, logSpace, logScale)
+}
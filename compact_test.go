@@ -0,0 +1,97 @@
+package viterbi
+
+import (
+	"math"
+	"testing"
+)
+
+func TestViterbiEvalPathCompact(t *testing.T) {
+	for _, v := range []*Viterbi{randomModel(25, 40, 7), randomModel(3, 5, 11)} {
+		expected, err := v.EvalPath()
+		if err != nil {
+			t.Fatal(err)
+		}
+		vpath, err := v.EvalPathCompact()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if vpath.Probability != expected.Probability {
+			t.Error(
+				"Probability has to be", expected.Probability, "but got", vpath.Probability,
+			)
+		}
+		for i := range expected.Path {
+			if vpath.Path[i] != expected.Path[i] {
+				t.Error(
+					"State", i, "has to be", expected.Path[i], "but got", vpath.Path[i],
+				)
+			}
+		}
+	}
+
+	v, _, _ := healthModel()
+	logV := logModel(v)
+	expected, err := logV.EvalPathLogProbabilities()
+	if err != nil {
+		t.Fatal(err)
+	}
+	vpath, err := logV.EvalPathLogProbabilitiesCompact()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if vpath.Probability != expected.Probability {
+		t.Error(
+			"Logarithmic probability has to be", expected.Probability, "but got", vpath.Probability,
+		)
+	}
+	for i := range expected.Path {
+		if vpath.Path[i] != expected.Path[i] {
+			t.Error(
+				"State", i, "has to be", expected.Path[i], "but got", vpath.Path[i],
+			)
+		}
+	}
+}
+
+func TestViterbiEvalPathCompactPredecessors(t *testing.T) {
+	v, incStates, _ := healthModel()
+	v.SetPredecessors(incStates[0], []State{incStates[1]})
+	expected, err := v.EvalPath()
+	if err != nil {
+		t.Fatal(err)
+	}
+	vpath, err := v.EvalPathCompact()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if vpath.String() != expected.String() {
+		t.Error(
+			"Path has to be", expected, ", but got", vpath,
+		)
+	}
+}
+
+func TestViterbiEvalPathCompactLongSequence(t *testing.T) {
+	// Classic probabilities of 800 observations underflow without rescaling
+	v := randomModel(5, 800, 3)
+	expected, err := v.EvalPath()
+	if err != nil {
+		t.Fatal(err)
+	}
+	vpath, err := v.EvalPathCompact()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if math.Abs(vpath.LogProbability-expected.LogProbability) > 1e-9 {
+		t.Error(
+			"Logarithmic probability has to be", expected.LogProbability, ", but got", vpath.LogProbability,
+		)
+	}
+	for i := range expected.Path {
+		if vpath.Path[i] != expected.Path[i] {
+			t.Error(
+				"State", i, "has to be", expected.Path[i], "but got", vpath.Path[i],
+			)
+		}
+	}
+}
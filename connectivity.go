@@ -0,0 +1,52 @@
+package viterbi
+
+// CheckConnectivity returns states without any possible outgoing transition: path reaching such state can't be continued.
+// Stored, default and computed (see SetTransitionFunc) transitions are taken into account; zero probabilities are not possible.
+// States are returned in order they have been added
+// When every probability is in [0;1]
+func (v *Viterbi) CheckConnectivity() []State {
+	return v.checkConnectivity(false, false)
+}
+
+// CheckConnectivityLogProbabilities is the same as CheckConnectivity, but when every probability is logarithmic (-Inf is not possible)
+func (v *Viterbi) CheckConnectivityLogProbabilities() []State {
+	return v.checkConnectivity(true, false)
+}
+
+// CheckIncomingConnectivity returns states without any possible incoming transition: such states could only start the path.
+// Restrictions set by SetPredecessors are taken into account
+// When every probability is in [0;1]
+func (v *Viterbi) CheckIncomingConnectivity() []State {
+	return v.checkConnectivity(false, true)
+}
+
+// CheckIncomingConnectivityLogProbabilities is the same as CheckIncomingConnectivity, but when every probability is logarithmic
+func (v *Viterbi) CheckIncomingConnectivityLogProbabilities() []State {
+	return v.checkConnectivity(true, true)
+}
+
+func (v *Viterbi) checkConnectivity(logSpace bool, incoming bool) []State {
+	outgoing := make(map[int]struct{}, len(v.states))
+	reached := make(map[int]struct{}, len(v.states))
+	for _, to := range v.states {
+		for _, from := range v.predecessorsOf(to) {
+			prob, ok, err := v.transitionProbability(from, to, logSpace)
+			if err != nil || !ok || impossible(logSpace, prob) {
+				continue
+			}
+			outgoing[from.ID()] = struct{}{}
+			reached[to.ID()] = struct{}{}
+		}
+	}
+	connected := outgoing
+	if incoming {
+		connected = reached
+	}
+	states := []State{}
+	for _, st := range v.states {
+		if _, ok := connected[st.ID()]; !ok {
+			states = append(states, st)
+		}
+	}
+	return states
+}
@@ -0,0 +1,59 @@
+package viterbi
+
+import (
+	"math"
+	"testing"
+)
+
+func TestViterbiCheckConnectivity(t *testing.T) {
+	v, incStates, _ := healthModel()
+	if states := v.CheckConnectivity(); len(states) != 0 {
+		t.Error(
+			"Every state has to have outgoing transition, but got", states,
+		)
+	}
+	if states := v.CheckIncomingConnectivity(); len(states) != 0 {
+		t.Error(
+			"Every state has to have incoming transition, but got", states,
+		)
+	}
+
+	dead := CustomState{Name: "Dead", id: 3}
+	v.AddState(dead)
+	v.PutTransitionProbability(incStates[1], dead, 0.1)
+	if states := v.CheckConnectivity(); len(states) != 1 || states[0].ID() != dead.ID() {
+		t.Error(
+			"State without outgoing transition has to be [Dead], but got", states,
+		)
+	}
+	// Zero probability is not possible transition
+	v.PutTransitionProbability(dead, incStates[0], 0)
+	if states := v.CheckConnectivity(); len(states) != 1 || states[0].ID() != dead.ID() {
+		t.Error(
+			"State with zero outgoing transition has to be [Dead], but got", states,
+		)
+	}
+
+	born := CustomState{Name: "Born", id: 4}
+	v.AddState(born)
+	v.PutTransitionProbability(born, incStates[0], 1.0)
+	if states := v.CheckIncomingConnectivity(); len(states) != 1 || states[0].ID() != born.ID() {
+		t.Error(
+			"State without incoming transition has to be [Born], but got", states,
+		)
+	}
+
+	logV := v.ToLog()
+	// log(0) is -Inf
+	if states := logV.CheckConnectivityLogProbabilities(); len(states) != 1 || states[0].ID() != dead.ID() {
+		t.Error(
+			"State with -Inf outgoing transitions only has to be [Dead], but got", states,
+		)
+	}
+	logV.SetDefaultTransitionProbability(math.Log(0.01))
+	if states := logV.CheckIncomingConnectivityLogProbabilities(); len(states) != 0 {
+		t.Error(
+			"Default transition has to connect every state, but got", states,
+		)
+	}
+}
@@ -0,0 +1,40 @@
+package viterbi
+
+import (
+	"math"
+)
+
+// consistencyTolerance is allowed excess of logarithm of Viterbi probability over logarithm of Forward probability
+const consistencyTolerance = 1e-9
+
+// CheckViterbiVsForward evaluates both the best path (EvalPath) and total probability of observations (Forward) and checks
+// that the former does not exceed the latter: single path can't be more probable than sum over all paths.
+// Violation (ok is false) points to inconsistent model or evaluation bug, so it's meant as sanity check e.g. in tests of custom models.
+// Both probabilities are returned as logarithms.
+// Forward does not rescale classic probabilities: use CheckViterbiVsForwardLogProbabilities for long sequences
+// When every probability is in [0;1]
+func (v *Viterbi) CheckViterbiVsForward() (viterbiLogP, forwardLogP float64, ok bool, err error) {
+	return v.checkViterbiVsForward(false)
+}
+
+// CheckViterbiVsForwardLogProbabilities is the same as CheckViterbiVsForward, but when every probability is logarithmic
+func (v *Viterbi) CheckViterbiVsForwardLogProbabilities() (viterbiLogP, forwardLogP float64, ok bool, err error) {
+	return v.checkViterbiVsForward(true)
+}
+
+func (v *Viterbi) checkViterbiVsForward(logSpace bool) (float64, float64, bool, error) {
+	path, err := v.evalPath(logSpace)
+	if err != nil {
+		return math.Inf(-1), math.Inf(-1), false, err
+	}
+	alpha, err := v.forward(logSpace)
+	if err != nil {
+		return math.Inf(-1), math.Inf(-1), false, err
+	}
+	total, err := v.totalProbability(alpha, logSpace)
+	if err != nil {
+		return math.Inf(-1), math.Inf(-1), false, err
+	}
+	forwardLogP := logProbability(logSpace, total)
+	return path.LogProbability, forwardLogP, path.LogProbability <= forwardLogP+consistencyTolerance, nil
+}
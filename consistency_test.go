@@ -0,0 +1,37 @@
+package viterbi
+
+import (
+	"math"
+	"testing"
+)
+
+func TestViterbiCheckViterbiVsForward(t *testing.T) {
+	v, _, _ := healthModel()
+	viterbiLogP, forwardLogP, ok, err := v.CheckViterbiVsForward()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !ok || math.Abs(viterbiLogP-math.Log(0.01512)) > 1e-12 || math.Abs(forwardLogP-math.Log(0.03628)) > 1e-12 {
+		t.Error(
+			"Expected consistent log probabilities log(0.01512) and log(0.03628), but got", viterbiLogP, forwardLogP, ok,
+		)
+	}
+
+	logViterbiLogP, logForwardLogP, ok, err := logModel(v).CheckViterbiVsForwardLogProbabilities()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !ok || math.Abs(logViterbiLogP-viterbiLogP) > 1e-12 || math.Abs(logForwardLogP-forwardLogP) > 1e-12 {
+		t.Error(
+			"Logarithmic check has to match classic one, but got", logViterbiLogP, logForwardLogP, ok,
+		)
+	}
+
+	// Inequality has to hold for every model
+	r := randomModel(5, 4, 1)
+	if _, _, ok, err := r.CheckViterbiVsForward(); err != nil || !ok {
+		t.Error(
+			"Random model has to be consistent, but got", ok, err,
+		)
+	}
+}
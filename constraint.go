@@ -0,0 +1,31 @@
+package viterbi
+
+// AddConstraint restricts states which could be chosen for observation with given index (timestep) to allowed ones:
+// other states are treated as if they could not emit the observation. Observations without constraints allow every state.
+// Several constraints for the same timestep narrow each other (only states allowed by all of them remain).
+// Constraints are applied by every evaluator and kept until ResetObservations (or Reset) is called
+func (v *Viterbi) AddConstraint(timestep int, allowed []State) {
+	ids := make(map[int]struct{}, len(allowed))
+	for _, st := range allowed {
+		if prev, ok := v.constraints[timestep]; ok {
+			if _, ok := prev[st.ID()]; !ok {
+				continue
+			}
+		}
+		ids[st.ID()] = struct{}{}
+	}
+	if v.constraints == nil {
+		v.constraints = make(map[int]map[int]struct{})
+	}
+	v.constraints[timestep] = ids
+}
+
+// allowed reports whether state satisfies constraint of observation t
+func (v *Viterbi) allowed(s State, t int) bool {
+	ids, ok := v.constraints[t]
+	if !ok {
+		return true
+	}
+	_, ok = ids[s.ID()]
+	return ok
+}
@@ -0,0 +1,46 @@
+package viterbi
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestViterbiAddConstraint(t *testing.T) {
+	v, incStates, _ := healthModel()
+	correctPath, err := v.EvalPath()
+	if err != nil {
+		t.Fatal(err)
+	}
+	// Unconstrained path is [Healty, Healty, Fever]: pin 'Fever' at the second observation
+	v.AddConstraint(1, []State{incStates[1]})
+
+	vpath, err := v.EvalPath()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if vpath.Path[1].ID() != incStates[1].ID() {
+		t.Error(
+			"State on position 1 has to be", incStates[1], ", but got", vpath.Path[1],
+		)
+	}
+	if vpath.Probability >= correctPath.Probability {
+		t.Error(
+			"Constrained probability has to be less than", correctPath.Probability, ", but got", vpath.Probability,
+		)
+	}
+
+	// Narrowing by disjoint set leaves no state allowed
+	v.AddConstraint(1, []State{incStates[0]})
+	if _, err := v.EvalPath(); !errors.Is(err, ErrPathBroken) {
+		t.Error(
+			"Error has to be ErrPathBroken, but got", err,
+		)
+	}
+
+	v.ResetObservations()
+	if len(v.constraints) != 0 {
+		t.Error(
+			"Constraints have to be cleared, but got", v.constraints,
+		)
+	}
+}
@@ -0,0 +1,17 @@
+package viterbi
+
+import (
+	"math"
+)
+
+// ToLog returns copy of the model where every start, emission and transition probability (and defaults) is replaced by its logarithm.
+// Zero probability becomes -Inf. Result is meant to be evaluated via *LogProbabilities methods
+func (v *Viterbi) ToLog() *Viterbi {
+	return v.withProbabilities(math.Log)
+}
+
+// FromLog is inverse of ToLog: it returns copy of the model where every logarithmic probability is replaced by its exponent.
+// -Inf becomes zero probability
+func (v *Viterbi) FromLog() *Viterbi {
+	return v.withProbabilities(math.Exp)
+}
@@ -0,0 +1,64 @@
+package viterbi
+
+import (
+	"math"
+	"testing"
+)
+
+func TestViterbiToLog(t *testing.T) {
+	v, incStates, incomingObservations := healthModel()
+	delete(v.transitionProbabilities, TransitionHash{incStates[1].ID(), incStates[1].ID()})
+	v.PutTransitionProbability(incStates[1], incStates[1], 0)
+	correctPath, err := v.EvalPath()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	logV := v.ToLog()
+	if val, _ := logV.GetTransitionProbability(incStates[1], incStates[1]); !math.IsInf(val, -1) {
+		t.Error(
+			"Logarithm of zero probability has to be -Inf, but got", val,
+		)
+	}
+	logPath, err := logV.EvalPathLogProbabilities()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if math.Abs(math.Exp(logPath.Probability)-correctPath.Probability) > 1e-12 {
+		t.Error(
+			"Probability has to be", correctPath.Probability, ", but got", math.Exp(logPath.Probability),
+		)
+	}
+	for i := range correctPath.Path {
+		if logPath.Path[i].ID() != correctPath.Path[i].ID() {
+			t.Error(
+				"State on position", i, "has to be", correctPath.Path[i], ", but got", logPath.Path[i],
+			)
+		}
+	}
+
+	// Source model has not to be changed
+	if val, _ := v.GetStartProbability(incStates[0]); val != 0.6 {
+		t.Error(
+			"Start probability of source model has to be 0.6, but got", val,
+		)
+	}
+
+	back := logV.FromLog()
+	for _, s := range incStates {
+		for _, obs := range incomingObservations {
+			want, _ := v.GetEmissionProbability(s, obs)
+			got, _ := back.GetEmissionProbability(s, obs)
+			if math.Abs(want-got) > 1e-12 {
+				t.Error(
+					"Emission probability has to be", want, ", but got", got,
+				)
+			}
+		}
+	}
+	if val, _ := back.GetTransitionProbability(incStates[1], incStates[1]); val != 0 {
+		t.Error(
+			"Exponent of -Inf has to be zero probability, but got", val,
+		)
+	}
+}
@@ -0,0 +1,70 @@
+package viterbi
+
+import (
+	"sort"
+)
+
+// IncStart counts occurrence of the state at the start of a sequence. Counts are converted into probabilities by Finalize
+func (m *Model) IncStart(s State) {
+	mustState(s, "IncStart")
+	if m.startCounts == nil {
+		m.startCounts = make(map[int]float64)
+	}
+	m.startCounts[s.ID()]++
+}
+
+// IncEmission counts emission of observation by the state
+func (m *Model) IncEmission(s State, obs Observation) {
+	mustState(s, "IncEmission")
+	mustObservation(obs, "IncEmission")
+	if m.emissionCounts == nil {
+		m.emissionCounts = make(map[EmissionHash]float64)
+	}
+	m.emissionCounts[EmissionHash{s.ID(), obs.ID()}]++
+}
+
+// IncTransition counts transition between states
+func (m *Model) IncTransition(from, to State) {
+	mustState(from, "IncTransition")
+	mustState(to, "IncTransition")
+	if m.transitionCounts == nil {
+		m.transitionCounts = make(map[TransitionHash]float64)
+	}
+	m.transitionCounts[m.transitionKey(from, to)]++
+}
+
+// Finalize replaces stored probabilities by counts accumulated via IncStart, IncEmission and IncTransition normalized into probabilities.
+// Smoothing is additive pseudo-count for every start, emission and transition (1 is Laplace smoothing, 0 disables it);
+// emissions are smoothed over every counted and added observation. Counts are cleared afterwards
+func (v *Viterbi) Finalize(smoothing float64) error {
+	var (
+		startCounts      = make(map[int]float64, len(v.startCounts))
+		emissionCounts   = make(map[EmissionHash]float64, len(v.emissionCounts))
+		transitionCounts = make(map[TransitionHash]float64, len(v.transitionCounts))
+		vocabulary       = make(map[int]struct{})
+	)
+	for id, count := range v.startCounts {
+		startCounts[id] = count
+	}
+	for key, count := range v.emissionCounts {
+		emissionCounts[key] = count
+		vocabulary[key.observation] = struct{}{}
+	}
+	for key, count := range v.transitionCounts {
+		transitionCounts[key] = count
+	}
+	for _, obs := range v.observations {
+		vocabulary[obs.ID()] = struct{}{}
+	}
+	vocabularyIDs := make([]int, 0, len(vocabulary))
+	for id := range vocabulary {
+		vocabularyIDs = append(vocabularyIDs, id)
+	}
+	sort.Ints(vocabularyIDs)
+
+	if err := v.applyCounts(startCounts, emissionCounts, transitionCounts, vocabularyIDs, smoothing); err != nil {
+		return err
+	}
+	v.startCounts, v.emissionCounts, v.transitionCounts = nil, nil, nil
+	return nil
+}
@@ -0,0 +1,74 @@
+package viterbi
+
+import (
+	"math"
+	"testing"
+)
+
+func TestViterbiFinalize(t *testing.T) {
+	_, incStates, incomingObservations := healthModel()
+	v := New()
+	for i := range incStates {
+		v.AddState(incStates[i])
+	}
+	for i := range incomingObservations {
+		v.AddObservation(incomingObservations[i])
+	}
+
+	v.IncStart(incStates[0])
+	v.IncStart(incStates[0])
+	v.IncStart(incStates[1])
+	v.IncEmission(incStates[0], incomingObservations[0])
+	v.IncEmission(incStates[0], incomingObservations[1])
+	v.IncEmission(incStates[1], incomingObservations[2])
+	v.IncTransition(incStates[0], incStates[1])
+	v.IncTransition(incStates[1], incStates[1])
+	v.IncTransition(incStates[1], incStates[0])
+
+	if err := v.Finalize(0); err != nil {
+		t.Fatal(err)
+	}
+	if val, _ := v.GetStartProbability(incStates[0]); math.Abs(val-2.0/3.0) > 1e-12 {
+		t.Error(
+			"Start probability of 'Healty' has to be 2/3, but got", val,
+		)
+	}
+	if val, _ := v.GetEmissionProbability(incStates[0], incomingObservations[1]); val != 0.5 {
+		t.Error(
+			"Emission of 'cold' by 'Healty' has to be 0.5, but got", val,
+		)
+	}
+	if val, _ := v.GetTransitionProbability(incStates[0], incStates[1]); val != 1 {
+		t.Error(
+			"Transition probability from 'Healty' to 'Fever' has to be 1, but got", val,
+		)
+	}
+	if v.startCounts != nil || v.emissionCounts != nil || v.transitionCounts != nil {
+		t.Error(
+			"Counts have to be cleared by Finalize",
+		)
+	}
+
+	v.IncStart(incStates[0])
+	v.IncEmission(incStates[0], incomingObservations[0])
+	if err := v.Finalize(1); err != nil {
+		t.Fatal(err)
+	}
+	// (1+1)/(1+2) for the counted state, 1/3 for the other one
+	if val, _ := v.GetStartProbability(incStates[1]); math.Abs(val-1.0/3.0) > 1e-12 {
+		t.Error(
+			"Smoothed start probability of 'Fever' has to be 1/3, but got", val,
+		)
+	}
+	// (1+1)/(1+3) over 3 observations
+	if val, _ := v.GetEmissionProbability(incStates[0], incomingObservations[0]); val != 0.5 {
+		t.Error(
+			"Smoothed emission of 'normal' by 'Healty' has to be 0.5, but got", val,
+		)
+	}
+	if val, _ := v.GetTransitionProbability(incStates[1], incStates[0]); val != 0.5 {
+		t.Error(
+			"Smoothed transition probability from 'Fever' to 'Healty' has to be 0.5, but got", val,
+		)
+	}
+}
@@ -0,0 +1,43 @@
+package viterbi
+
+import (
+	"encoding/csv"
+	"fmt"
+	"io"
+	"strconv"
+)
+
+// WriteCSV writes decoded path as CSV table with header "index,observation,state,step_probability" and one row per observation:
+// index of the observation, ID of the observation, ID of chosen state and local probability of the path at the observation (see StepProbabilities).
+// Step probability is left empty when the path has none
+func WriteCSV(w io.Writer, path ViterbiPath, obs []Observation) error {
+	stateLabel := func(st State) string {
+		return strconv.Itoa(st.ID())
+	}
+	obsLabel := func(o Observation) string {
+		return strconv.Itoa(o.ID())
+	}
+	return WriteCSVWith(w, path, obs, stateLabel, obsLabel)
+}
+
+// WriteCSVWith is the same as WriteCSV, but states and observations are rendered by stateLabel and obsLabel instead of their IDs
+func WriteCSVWith(w io.Writer, path ViterbiPath, obs []Observation, stateLabel func(st State) string, obsLabel func(o Observation) string) error {
+	if len(path.Path) != len(obs) {
+		return fmt.Errorf("%w: got %d states for %d observations", ErrPathLength, len(path.Path), len(obs))
+	}
+	cw := csv.NewWriter(w)
+	if err := cw.Write([]string{"index", "observation", "state", "step_probability"}); err != nil {
+		return err
+	}
+	for t := range obs {
+		step := ""
+		if t < len(path.StepProbabilities) {
+			step = strconv.FormatFloat(path.StepProbabilities[t], 'g', -1, 64)
+		}
+		if err := cw.Write([]string{strconv.Itoa(t), obsLabel(obs[t]), stateLabel(path.Path[t]), step}); err != nil {
+			return err
+		}
+	}
+	cw.Flush()
+	return cw.Error()
+}
@@ -0,0 +1,70 @@
+package viterbi
+
+import (
+	"errors"
+	"strings"
+	"testing"
+)
+
+func TestWriteCSV(t *testing.T) {
+	v, _, _ := healthModel()
+	vpath, err := v.EvalPath()
+	if err != nil {
+		t.Fatal(err)
+	}
+	var b strings.Builder
+	if err := WriteCSV(&b, vpath, v.Observations()); err != nil {
+		t.Fatal(err)
+	}
+	expected := "index,observation,state,step_probability\n" +
+		"0,1,1,0.3\n" +
+		"1,2,1,0.27999999999999997\n" +
+		"2,3,2,0.18\n"
+	if b.String() != expected {
+		t.Error(
+			"CSV has to be\n", expected, "but got\n", b.String(),
+		)
+	}
+
+	vpath.StepProbabilities = nil
+	b.Reset()
+	if err := WriteCSV(&b, vpath, v.Observations()); err != nil {
+		t.Fatal(err)
+	}
+	if !strings.HasSuffix(b.String(), "2,3,2,\n") {
+		t.Error(
+			"Step probability has to be empty, but got", b.String(),
+		)
+	}
+
+	if err := WriteCSV(&b, vpath, v.Observations()[:1]); !errors.Is(err, ErrPathLength) {
+		t.Error(
+			"Error has to be ErrPathLength, but got", err,
+		)
+	}
+}
+
+func TestWriteCSVWith(t *testing.T) {
+	v, _, _ := healthModel()
+	vpath, err := v.EvalPath()
+	if err != nil {
+		t.Fatal(err)
+	}
+	vpath.StepProbabilities = nil
+	var b strings.Builder
+	obsLabel := func(o Observation) string {
+		return o.(CustomObservation).Name
+	}
+	if err := WriteCSVWith(&b, vpath, v.Observations(), LabelsByID(map[int]string{1: "Healthy", 2: "Fever"}), obsLabel); err != nil {
+		t.Fatal(err)
+	}
+	expected := "index,observation,state,step_probability\n" +
+		"0,normal,Healthy,\n" +
+		"1,cold,Healthy,\n" +
+		"2,dizzy,Fever,\n"
+	if b.String() != expected {
+		t.Error(
+			"CSV has to be\n", expected, "but got\n", b.String(),
+		)
+	}
+}
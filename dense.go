@@ -0,0 +1,44 @@
+package viterbi
+
+// denseColumn is trellis column indexed by position of state in states slice.
+// Cells of the previous column are read for every (state, predecessor) pair, so the column is converted
+// to dense form once instead of hashing interface keys on every read
+type denseColumn struct {
+	values []ViterbiVal
+	set    []bool
+}
+
+// stateIndex returns dense index (position in states slice) of every state ID
+func (m *Model) stateIndex() map[int]int {
+	index := make(map[int]int, len(m.states))
+	for i := len(m.states) - 1; i >= 0; i-- {
+		index[m.states[i].ID()] = i
+	}
+	return index
+}
+
+// newDenseColumn allocates empty dense column of n states
+func newDenseColumn(n int) denseColumn {
+	return denseColumn{
+		values: make([]ViterbiVal, n),
+		set:    make([]bool, n),
+	}
+}
+
+// reset marks every cell of the column as unset
+func (column denseColumn) reset() {
+	for i := range column.set {
+		column.set[i] = false
+	}
+}
+
+// toDense converts trellis column to dense form
+func (m *Model) toDense(column map[State]ViterbiVal) denseColumn {
+	dense := newDenseColumn(len(m.states))
+	for i, st := range m.states {
+		if value, ok := column[st]; ok {
+			dense.values[i], dense.set[i] = value, true
+		}
+	}
+	return dense
+}
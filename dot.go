@@ -0,0 +1,59 @@
+package viterbi
+
+import (
+	"fmt"
+	"io"
+	"strings"
+)
+
+// TrellisDOT writes trellis (see EvalPathWithTrellis) as Graphviz DOT graph: one node per reachable state of every observation
+// and one edge per back-pointer labeled with probability of the cell it leads to.
+// Nodes and edges of given path (the best one, usually) are highlighted
+func TrellisDOT(w io.Writer, trellis [][]TrellisCell, path ViterbiPath) error {
+	return TrellisDOTWith(w, trellis, path, func(st State) string {
+		return fmt.Sprint(st)
+	})
+}
+
+// TrellisDOTWith is the same as TrellisDOT, but every state is rendered by label
+func TrellisDOTWith(w io.Writer, trellis [][]TrellisCell, path ViterbiPath, label func(st State) string) error {
+	onPath := func(t int, st State) bool {
+		return t < len(path.Path) && path.Path[t] != nil && st != nil && path.Path[t].ID() == st.ID()
+	}
+	var b strings.Builder
+	b.WriteString("digraph trellis {\n")
+	b.WriteString("\trankdir=LR;\n")
+	b.WriteString("\tnode [shape=box];\n")
+	for t := range trellis {
+		fmt.Fprintf(&b, "\tsubgraph step_%d {\n", t)
+		b.WriteString("\t\trank=same;\n")
+		for _, cell := range trellis[t] {
+			attrs := ""
+			if onPath(t, cell.State) {
+				attrs = ", color=red, penwidth=2"
+			}
+			fmt.Fprintf(&b, "\t\t%s [label=%q%s];\n", dotNode(t, cell.State), fmt.Sprintf("t=%d: %s\n%g", t, label(cell.State), cell.Probability), attrs)
+		}
+		b.WriteString("\t}\n")
+	}
+	for t := 1; t < len(trellis); t++ {
+		for _, cell := range trellis[t] {
+			if cell.Previous == nil {
+				continue
+			}
+			attrs := ""
+			if onPath(t, cell.State) && onPath(t-1, cell.Previous) {
+				attrs = ", color=red, penwidth=2"
+			}
+			fmt.Fprintf(&b, "\t%s -> %s [label=\"%g\"%s];\n", dotNode(t-1, cell.Previous), dotNode(t, cell.State), cell.Probability, attrs)
+		}
+	}
+	b.WriteString("}\n")
+	_, err := io.WriteString(w, b.String())
+	return err
+}
+
+// dotNode returns identifier of DOT node for the state at observation t
+func dotNode(t int, st State) string {
+	return fmt.Sprintf("t%d_s%d", t, st.ID())
+}
@@ -0,0 +1,47 @@
+package viterbi
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+)
+
+func TestTrellisDOT(t *testing.T) {
+	v, _, _ := healthModel()
+	vpath, trellis, err := v.EvalPathWithTrellis()
+	if err != nil {
+		t.Fatal(err)
+	}
+	var buf bytes.Buffer
+	if err := TrellisDOT(&buf, trellis, vpath); err != nil {
+		t.Fatal(err)
+	}
+	dot := buf.String()
+	if !strings.HasPrefix(dot, "digraph trellis {") || !strings.HasSuffix(dot, "}\n") {
+		t.Error(
+			"Output has to be DOT graph, but got", dot,
+		)
+	}
+	// 2 states for 3 observations
+	if nodes := strings.Count(dot, "[label=\"t="); nodes != 6 {
+		t.Error(
+			"Number of nodes has to be 6, but got", nodes,
+		)
+	}
+	if edges := strings.Count(dot, " -> "); edges != 4 {
+		t.Error(
+			"Number of edges has to be 4, but got", edges,
+		)
+	}
+	// Best path is [Healty, Healty, Fever]: 3 nodes and 2 edges are highlighted
+	if highlighted := strings.Count(dot, "color=red"); highlighted != 5 {
+		t.Error(
+			"Number of highlighted nodes and edges has to be 5, but got", highlighted,
+		)
+	}
+	if !strings.Contains(dot, "t1_s1 -> t2_s2") {
+		t.Error(
+			"Edge from 'Healty' to 'Fever' has to be present, but got", dot,
+		)
+	}
+}
@@ -0,0 +1,193 @@
+package viterbi
+
+import (
+	"fmt"
+	"math"
+)
+
+// DurationHash is key of duration probability: ID of state and number of consecutive observations spent in it
+type DurationHash struct {
+	State    int
+	Duration int
+}
+
+// durationBack is back-pointer of duration trellis: index of state of previous segment (-1 for the first segment) and duration of current one
+type durationBack struct {
+	prev     int
+	duration int
+}
+
+// SetDurationProbability puts probability of the state to last exactly d consecutive observations (explicit-duration, i.e. hidden semi-Markov model).
+// Duration probabilities are used by EvalPathDuration only
+func (m *Model) SetDurationProbability(s State, d int, val float64) {
+	mustState(s, "SetDurationProbability")
+	if m.durationProbabilities == nil {
+		m.durationProbabilities = make(map[DurationHash]float64)
+	}
+	m.durationProbabilities[DurationHash{s.ID(), d}] = val
+}
+
+// EvalPathDuration is the same as EvalPath, but for hidden semi-Markov model: path is a sequence of segments, and the state of every segment
+// lasts number of observations drawn from its duration probabilities (see SetDurationProbability) instead of geometric distribution implied by self-transition.
+// Self-transition of state with duration probabilities is ignored (segment could not be followed by segment of the same state),
+// while state without them lasts single observation and could repeat via self-transition as usual.
+// Path is returned per observation, so states of long segments are repeated. Step probabilities are not evaluated.
+// Evaluation takes O(T·N²·D) time for N states and maximum duration D.
+// Classic probabilities are not rescaled: use EvalPathDurationLogProbabilities for long sequences
+// When every probability is in [0;1]
+// Equal probabilities are resolved in favour of the shorter duration, then according to the tie-breaker (see SetTieBreaker), in favour of the state with the lowest ID() by default
+func (v *Viterbi) EvalPathDuration() (ViterbiPath, error) {
+	return v.evalPathDuration(false)
+}
+
+// EvalPathDurationLogProbabilities is the same as EvalPathDuration, but when every probability is logarithmic
+func (v *Viterbi) EvalPathDurationLogProbabilities() (ViterbiPath, error) {
+	return v.evalPathDuration(true)
+}
+
+func (v *Viterbi) evalPathDuration(logSpace bool) (ViterbiPath, error) {
+	if err := v.validate(); err != nil {
+		return ViterbiPath{}, err
+	}
+
+	n, T := len(v.states), len(v.observations)
+	maxDuration := make([]int, n)
+	index := v.stateIndex()
+	for key := range v.durationProbabilities {
+		if i, ok := index[key.State]; ok && key.Duration > maxDuration[i] {
+			maxDuration[i] = key.Duration
+		}
+	}
+
+	emissions := make([][]float64, T)
+	emitted := make([][]bool, T)
+	for t := range emissions {
+		emissions[t], emitted[t] = make([]float64, n), make([]bool, n)
+		unknown := v.unknownAt(t)
+		for i, s := range v.states {
+			emissionProb, ok, err := v.emissionProbability(s, t, unknown, logSpace)
+			if err != nil {
+				return ViterbiPath{}, err
+			}
+			emissions[t][i], emitted[t][i] = emissionProb, ok
+		}
+	}
+
+	// delta[t][i] is probability of the best path whose segment of state i ends at observation t
+	delta := make([][]float64, T)
+	reached := make([][]bool, T)
+	back := make([][]durationBack, T)
+	for t := 0; t < T; t++ {
+		delta[t], reached[t], back[t] = make([]float64, n), make([]bool, n), make([]durationBack, n)
+		for i, s := range v.states {
+			found, best, bestBack := false, 0.0, durationBack{}
+			limit := maxDuration[i]
+			if limit == 0 {
+				limit = 1
+			}
+			segment := one(logSpace)
+			for d := 1; d <= limit && d <= t+1; d++ {
+				first := t - d + 1
+				if !emitted[first][i] {
+					// Segment can't cover observation without emission
+					break
+				}
+				segment = combine(logSpace, segment, emissions[first][i])
+				durationProb, ok, err := v.durationProbability(s, d, maxDuration[i] > 0, logSpace)
+				if err != nil {
+					return ViterbiPath{}, err
+				}
+				if !ok {
+					continue
+				}
+				if first == 0 {
+					startProb, ok, err := v.startProbability(s, logSpace)
+					if err != nil {
+						return ViterbiPath{}, err
+					}
+					if !ok {
+						continue
+					}
+					prob := combine(logSpace, combine(logSpace, startProb, durationProb), segment)
+					if !found || prob > best {
+						found, best, bestBack = true, prob, durationBack{prev: -1, duration: d}
+					}
+					continue
+				}
+				for _, r := range v.predecessorsOf(s) {
+					j, ok := index[r.ID()]
+					if !ok || !reached[first-1][j] || j == i && maxDuration[i] > 0 {
+						continue
+					}
+					transitionProb, ok, err := v.transitionProbability(r, s, logSpace)
+					if err != nil {
+						return ViterbiPath{}, err
+					}
+					if !ok {
+						continue
+					}
+					prob := combine(logSpace, combine(logSpace, delta[first-1][j], transitionProb), combine(logSpace, durationProb, segment))
+					// Shorter duration is kept on tie, so states are compared within the same duration only
+					if !found || prob > best || bestBack.duration == d && v.preferState(prob, r, best, v.states[bestBack.prev]) {
+						found, best, bestBack = true, prob, durationBack{prev: j, duration: d}
+					}
+				}
+			}
+			if !found || impossible(logSpace, best) {
+				continue
+			}
+			delta[t][i], reached[t][i], back[t][i] = best, true, bestBack
+		}
+	}
+
+	last, maxPr := -1, math.Inf(-1)
+	for i, s := range v.states {
+		if !reached[T-1][i] {
+			continue
+		}
+		endProb, err := v.endProbability(s, logSpace)
+		if err != nil {
+			return ViterbiPath{}, err
+		}
+		prob := combine(logSpace, delta[T-1][i], endProb)
+		if last < 0 || v.preferState(prob, s, maxPr, v.states[last]) {
+			last, maxPr = i, prob
+		}
+	}
+	if last < 0 || impossible(logSpace, maxPr) {
+		return ViterbiPath{}, ErrNoValidPath
+	}
+
+	path := make([]State, T)
+	for t, i := T-1, last; t >= 0; {
+		b := back[t][i]
+		for k := 0; k < b.duration; k++ {
+			path[t-k] = v.states[i]
+		}
+		t, i = t-b.duration, b.prev
+	}
+	if logSpace {
+		return ViterbiPath{Probability: maxPr, LogProbability: maxPr, Path: path}, nil
+	}
+	return ViterbiPath{Probability: maxPr, LogProbability: math.Log(maxPr), Path: path}, nil
+}
+
+// durationProbability returns probability of the state to last d observations. State without duration probabilities (hasDurations is false)
+// lasts single observation with probability of one
+func (v *Viterbi) durationProbability(s State, d int, hasDurations bool, logSpace bool) (float64, bool, error) {
+	if !hasDurations {
+		return one(logSpace), d == 1, nil
+	}
+	durationProb, ok := v.durationProbabilities[DurationHash{s.ID(), d}]
+	if !ok {
+		return 0, false, nil
+	}
+	durationProb, ok = v.clampProbability(logSpace, durationProb)
+	if !ok {
+		return 0, false, fmt.Errorf("%w: duration probability %v of state %v for %d observations", ErrInvalidProbability, durationProb, s, d)
+	}
+	if impossible(logSpace, durationProb) {
+		return 0, false, nil
+	}
+	return durationProb, true, nil
+}
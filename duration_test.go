@@ -0,0 +1,60 @@
+package viterbi
+
+import (
+	"fmt"
+	"math"
+	"testing"
+)
+
+func TestViterbiEvalPathDuration(t *testing.T) {
+	v, incStates, _ := healthModel()
+	healthy, fever := incStates[0], incStates[1]
+
+	// States without duration probabilities behave as in plain HMM
+	vpath, err := v.EvalPathDuration()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if vpath.String() != "0.01512: [1 1 2]" {
+		t.Error(
+			"Path has to be '0.01512: [1 1 2]', but got", vpath,
+		)
+	}
+
+	// 'Healthy' lasts exactly 3 observations and 'Fever' single one, so the only complete path is single segment of 'Healthy'
+	v.SetDurationProbability(healthy, 3, 1)
+	v.SetDurationProbability(fever, 1, 1)
+	vpath, err = v.EvalPathDuration()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if fmt.Sprint(vpath.IDs()) != "[1 1 1]" || math.Abs(vpath.Probability-0.012) > 1e-12 {
+		t.Error(
+			"Path has to be '0.012: [1 1 1]', but got", vpath,
+		)
+	}
+
+	logPath, err := v.ToLog().EvalPathDurationLogProbabilities()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if fmt.Sprint(logPath.IDs()) != "[1 1 1]" || math.Abs(logPath.LogProbability-vpath.LogProbability) > 1e-12 {
+		t.Error(
+			"Logarithmic path has to match classic one, but got", logPath,
+		)
+	}
+
+	v.SetDurationProbability(healthy, 3, 0)
+	if _, err := v.EvalPathDuration(); err != ErrNoValidPath {
+		t.Error(
+			"Error has to be ErrNoValidPath, but got", err,
+		)
+	}
+
+	v.RemoveState(healthy)
+	if len(v.durationProbabilities) != 1 {
+		t.Error(
+			"Duration probabilities of removed state have to be removed, but got", v.durationProbabilities,
+		)
+	}
+}
@@ -0,0 +1,189 @@
+package viterbi
+
+import (
+	"errors"
+	"fmt"
+	"math"
+)
+
+var (
+	// ErrNoModels is returned when ensemble has no models
+	ErrNoModels = errors.New("no models have been passed")
+	// ErrEnsembleMismatch is returned when models of ensemble do not share states and observations
+	ErrEnsembleMismatch = errors.New("models of ensemble do not share states and observations")
+)
+
+// EvalEnsemble evaluates the most probable path for combination of models sharing states (by ID()) and observations sequence:
+// score of every start, emission, transition and end is weighted sum of logarithms of probabilities of the models.
+// Probability of returned path is such score (logarithmic). Pair which is impossible in any model is impossible in combination.
+// Settings of the first model (beam width, constraints and so on) are used for evaluation; missing transitions are impossible even in strict mode.
+// Minimum probability and ambiguity epsilon of classic models are converted into logarithmic scale of the score
+// When every probability is in [0;1]
+func EvalEnsemble(models []*Viterbi, weights []float64) (ViterbiPath, error) {
+	return evalEnsemble(models, weights, false)
+}
+
+// EvalEnsembleLogProbabilities is the same as EvalEnsemble, but when every probability is logarithmic
+func EvalEnsembleLogProbabilities(models []*Viterbi, weights []float64) (ViterbiPath, error) {
+	return evalEnsemble(models, weights, true)
+}
+
+func evalEnsemble(models []*Viterbi, weights []float64, logSpace bool) (ViterbiPath, error) {
+	combined, err := combineModels(models, weights, logSpace)
+	if err != nil {
+		return ViterbiPath{}, err
+	}
+	return combined.EvalPathLogProbabilities()
+}
+
+// combineModels builds logarithmic model holding weighted sums of probabilities of the models
+func combineModels(models []*Viterbi, weights []float64, logSpace bool) (*Viterbi, error) {
+	if len(models) == 0 {
+		return nil, ErrNoModels
+	}
+	if len(weights) != len(models) {
+		return nil, fmt.Errorf("%w: %d weights for %d models", ErrDimensionMismatch, len(weights), len(models))
+	}
+	base := models[0]
+	if err := base.validate(); err != nil {
+		return nil, err
+	}
+	for i, m := range models[1:] {
+		if err := checkEnsembleMember(base, m); err != nil {
+			return nil, fmt.Errorf("model %d: %w", i+1, err)
+		}
+	}
+
+	// Settings of the first model are kept, while probabilities are replaced by combined ones
+	combined := *base.Clone()
+	combined.startProbabilities = make(map[int]float64, len(base.states))
+	combined.endProbabilities = nil
+	combined.emissionProbabilities = make(map[EmissionHash]float64)
+	combined.transitionProbabilities = make(map[TransitionHash]float64, len(base.states)*len(base.states))
+	// Combined transitions are stored by (from, to) regardless of orientation of models
+	combined.transitionOrientation = TransitionFromTo
+	combined.transitionProbabilities2 = nil
+	combined.timedEmissions = make(map[TimedEmissionHash]float64, len(base.states)*len(base.observations))
+	combined.noEmissions = nil
+	combined.emissionFunc, combined.transitionFunc = nil, nil
+	combined.unknownEmission, combined.defaultEmission, combined.defaultTransition = nil, nil, nil
+	combined.emissionWeight, combined.transitionWeight = nil, nil
+	// Combined model is logarithmic: probabilities have already been clamped, and classic thresholds are converted
+	combined.tolerance = 0
+	if !logSpace {
+		if base.minProbability != nil {
+			minProbability := math.Log(*base.minProbability)
+			combined.minProbability = &minProbability
+		}
+		if base.ambiguityEpsilon != nil {
+			// Relative difference eps of classic probabilities is absolute difference -log(1-eps) of logarithms
+			eps := math.Inf(1)
+			if *base.ambiguityEpsilon < 1 {
+				eps = -math.Log1p(-*base.ambiguityEpsilon)
+			}
+			combined.ambiguityEpsilon = &eps
+		}
+	}
+
+	// score sums weighted logarithms of probabilities evaluated by fn for every model. Second return value is false when any of them is impossible
+	score := func(fn func(m *Viterbi) (float64, bool, error)) (float64, bool, error) {
+		sum := 0.0
+		for i, m := range models {
+			prob, ok, err := fn(m)
+			if err != nil || !ok {
+				return 0, false, err
+			}
+			if !logSpace {
+				prob = math.Log(prob)
+			}
+			if math.IsInf(prob, -1) {
+				return 0, false, nil
+			}
+			sum += weights[i] * prob
+		}
+		return sum, true, nil
+	}
+	for _, s := range base.states {
+		val, ok, err := score(func(m *Viterbi) (float64, bool, error) {
+			startProb, ok := m.startProbabilities[s.ID()]
+			if !ok {
+				return 0, false, nil
+			}
+			startProb, ok = m.clampProbability(logSpace, startProb)
+			if !ok {
+				return 0, false, fmt.Errorf("%w: start probability %v of state %v", ErrInvalidProbability, startProb, s)
+			}
+			return startProb, true, nil
+		})
+		if err != nil {
+			return nil, err
+		}
+		if ok {
+			combined.startProbabilities[s.ID()] = val
+		}
+		val, ok, err = score(func(m *Viterbi) (float64, bool, error) {
+			endProb, err := m.endProbability(s, logSpace)
+			return endProb, err == nil, err
+		})
+		if err != nil {
+			return nil, err
+		}
+		if !ok {
+			val = math.Inf(-1)
+		}
+		combined.PutEndProbability(s, val)
+		for t := range base.observations {
+			val, ok, err := score(func(m *Viterbi) (float64, bool, error) {
+				return m.emissionProbability(s, t, m.unknownAt(t), logSpace)
+			})
+			if err != nil {
+				return nil, err
+			}
+			if ok {
+				combined.timedEmissions[TimedEmissionHash{s.ID(), t}] = val
+			}
+		}
+		for _, to := range base.states {
+			val, ok, err := score(func(m *Viterbi) (float64, bool, error) {
+				prob, ok, err := m.transitionProbability(s, to, logSpace)
+				if errors.Is(err, ErrMissingTransition) {
+					// Every pair of states is scanned here, so missing transition is not an error even in strict mode
+					return 0, false, nil
+				}
+				return prob, ok, err
+			})
+			if err != nil {
+				return nil, err
+			}
+			if ok {
+				combined.transitionProbabilities[TransitionHash{s.ID(), to.ID()}] = val
+			}
+		}
+	}
+	return &combined, nil
+}
+
+// checkEnsembleMember checks that model has the same states (by ID()) and observations sequence as the base one
+func checkEnsembleMember(base, m *Viterbi) error {
+	if len(m.states) != len(base.states) {
+		return fmt.Errorf("%w: %d states instead of %d", ErrEnsembleMismatch, len(m.states), len(base.states))
+	}
+	ids := make(map[int]struct{}, len(base.states))
+	for _, st := range base.states {
+		ids[st.ID()] = struct{}{}
+	}
+	for _, st := range m.states {
+		if _, ok := ids[st.ID()]; !ok {
+			return fmt.Errorf("%w: state %v is missing in the first model", ErrEnsembleMismatch, st)
+		}
+	}
+	if len(m.observations) != len(base.observations) {
+		return fmt.Errorf("%w: %d observations instead of %d", ErrEnsembleMismatch, len(m.observations), len(base.observations))
+	}
+	for t := range m.observations {
+		if m.observations[t].ID() != base.observations[t].ID() {
+			return fmt.Errorf("%w: observation %d is %v instead of %v", ErrEnsembleMismatch, t, m.observations[t], base.observations[t])
+		}
+	}
+	return nil
+}
@@ -0,0 +1,106 @@
+package viterbi
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"testing"
+)
+
+func TestEvalEnsemble(t *testing.T) {
+	v, incStates, incomingObservations := healthModel()
+	// The other model prefers 'Fever' for every observation
+	other := v.Clone()
+	for _, obs := range incomingObservations {
+		other.emissionProbabilities[EmissionHash{incStates[0].ID(), obs.ID()}] = 0.01
+		other.emissionProbabilities[EmissionHash{incStates[1].ID(), obs.ID()}] = 0.99
+	}
+
+	for _, weights := range [][]float64{{1, 0}, {0.5, 0}} {
+		vpath, err := EvalEnsemble([]*Viterbi{v, other}, weights)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if fmt.Sprint(vpath.IDs()) != "[1 1 2]" {
+			t.Error(
+				"Path has to be [1 1 2], but got", vpath,
+			)
+		}
+		if math.Abs(vpath.Probability-weights[0]*math.Log(0.01512)) > 1e-12 {
+			t.Error(
+				"Score has to be", weights[0]*math.Log(0.01512), ", but got", vpath.Probability,
+			)
+		}
+	}
+
+	vpath, err := EvalEnsemble([]*Viterbi{v, other}, []float64{1, 1})
+	if err != nil {
+		t.Fatal(err)
+	}
+	for i, st := range vpath.Path {
+		if st != incStates[1] {
+			t.Error(
+				"State", i, "has to be", incStates[1], "but got", st,
+			)
+		}
+	}
+	logPath, err := EvalEnsembleLogProbabilities([]*Viterbi{logModel(v), logModel(other)}, []float64{1, 1})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if math.Abs(logPath.Probability-vpath.Probability) > 1e-12 {
+		t.Error(
+			"Score of logarithmic models has to be", vpath.Probability, ", but got", logPath.Probability,
+		)
+	}
+
+	if _, err := EvalEnsemble(nil, nil); err != ErrNoModels {
+		t.Error(
+			"Error has to be ErrNoModels, but got", err,
+		)
+	}
+	if _, err := EvalEnsemble([]*Viterbi{v, other}, []float64{1}); !errors.Is(err, ErrDimensionMismatch) {
+		t.Error(
+			"Error has to be ErrDimensionMismatch, but got", err,
+		)
+	}
+	other.AddObservation(incomingObservations[0])
+	if _, err := EvalEnsemble([]*Viterbi{v, other}, []float64{1, 1}); !errors.Is(err, ErrEnsembleMismatch) {
+		t.Error(
+			"Error has to be ErrEnsembleMismatch, but got", err,
+		)
+	}
+}
+
+func TestEvalEnsembleThresholds(t *testing.T) {
+	v, _, _ := healthModel()
+	v.SetMinProbability(1e-6)
+	v.SetAmbiguityEpsilon(0.5)
+	expected, err := v.EvalPath()
+	if err != nil {
+		t.Fatal(err)
+	}
+	vpath, err := EvalEnsemble([]*Viterbi{v}, []float64{1})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if fmt.Sprint(vpath.IDs()) != fmt.Sprint(expected.IDs()) {
+		t.Error(
+			"Path has to be", expected, ", but got", vpath,
+		)
+	}
+	if fmt.Sprint(vpath.Ambiguous) != fmt.Sprint(expected.Ambiguous) {
+		t.Error(
+			"Ambiguous observations have to be", expected.Ambiguous, ", but got", vpath.Ambiguous,
+		)
+	}
+
+	v.SetMinProbability(0.02)
+	_, err = EvalEnsemble([]*Viterbi{v}, []float64{1})
+	var tooLow *ProbabilityTooLowError
+	if !errors.As(err, &tooLow) || tooLow.ObservationIndex != 2 {
+		t.Error(
+			"Error has to be ProbabilityTooLowError at observation 2, but got", err,
+		)
+	}
+}
@@ -0,0 +1,78 @@
+package viterbi
+
+import (
+	"fmt"
+)
+
+// LabeledSequence is observations sequence with known hidden states
+type LabeledSequence struct {
+	States       []State
+	Observations []Observation
+}
+
+// Fit estimates probabilities from labeled sequences by maximum likelihood (counting):
+// start occurrences, transitions between states and emissions of observations by states are counted and normalized.
+// Smoothing is additive pseudo-count for every start, emission and transition (1 is Laplace smoothing, 0 disables it);
+// emissions are smoothed over distinct observations of sequences and added observations.
+// Stored probabilities are replaced; states found in sequences are added if needed
+func (v *Viterbi) Fit(pairs []LabeledSequence, smoothing float64) error {
+	if len(pairs) == 0 {
+		return ErrNoSequences
+	}
+	for i := range pairs {
+		if len(pairs[i].States) != len(pairs[i].Observations) {
+			return fmt.Errorf("%w: sequence %d has %d states for %d observations", ErrPathLength, i, len(pairs[i].States), len(pairs[i].Observations))
+		}
+	}
+
+	known := make(map[int]struct{}, len(v.states))
+	for _, st := range v.states {
+		known[st.ID()] = struct{}{}
+	}
+	vocabulary := append([]Observation{}, v.observations...)
+	var (
+		startCounts      = make(map[int]float64)
+		emissionCounts   = make(map[EmissionHash]float64)
+		transitionCounts = make(map[TransitionHash]float64)
+	)
+	for _, pair := range pairs {
+		for t, st := range pair.States {
+			if _, ok := known[st.ID()]; !ok {
+				known[st.ID()] = struct{}{}
+				v.AddState(st)
+			}
+			if t == 0 {
+				startCounts[st.ID()]++
+			} else {
+				transitionCounts[v.transitionKey(pair.States[t-1], st)]++
+			}
+			emissionCounts[EmissionHash{st.ID(), pair.Observations[t].ID()}]++
+		}
+		vocabulary = append(vocabulary, pair.Observations...)
+	}
+	vocabularyIDs := []int{}
+	for _, obs := range distinctObservations(vocabulary) {
+		vocabularyIDs = append(vocabularyIDs, obs.ID())
+	}
+	return v.applyCounts(startCounts, emissionCounts, transitionCounts, vocabularyIDs, smoothing)
+}
+
+// applyCounts replaces stored probabilities by counts with additive smoothing (emissions are smoothed over given observation IDs) and normalizes them
+func (v *Viterbi) applyCounts(startCounts map[int]float64, emissionCounts map[EmissionHash]float64, transitionCounts map[TransitionHash]float64, vocabulary []int, smoothing float64) error {
+	if smoothing > 0 {
+		for _, st := range v.states {
+			startCounts[st.ID()] += smoothing
+			for _, obs := range vocabulary {
+				emissionCounts[EmissionHash{st.ID(), obs}] += smoothing
+			}
+			for _, to := range v.states {
+				transitionCounts[TransitionHash{st.ID(), to.ID()}] += smoothing
+			}
+		}
+	}
+
+	v.startProbabilities = startCounts
+	v.emissionProbabilities = emissionCounts
+	v.transitionProbabilities = transitionCounts
+	return v.Normalize()
+}
@@ -0,0 +1,74 @@
+package viterbi
+
+import (
+	"errors"
+	"math"
+	"testing"
+)
+
+func TestViterbiFit(t *testing.T) {
+	_, incStates, incomingObservations := healthModel()
+	pairs := []LabeledSequence{
+		{
+			States:       []State{incStates[0], incStates[0], incStates[1]},
+			Observations: []Observation{incomingObservations[0], incomingObservations[1], incomingObservations[2]},
+		},
+		{
+			States:       []State{incStates[0], incStates[1], incStates[1]},
+			Observations: []Observation{incomingObservations[0], incomingObservations[2], incomingObservations[2]},
+		},
+	}
+
+	v := New()
+	if err := v.Fit(pairs, 0); err != nil {
+		t.Fatal(err)
+	}
+	if len(v.states) != 2 {
+		t.Fatal(
+			"Expected 2 states to be added, but got:", len(v.states),
+		)
+	}
+	if val, ok := v.GetStartProbability(incStates[0]); !ok || val != 1 {
+		t.Error(
+			"Start probability of 'Healty' has to be 1, but got", val, ok,
+		)
+	}
+	// 'Healty' → 'Healty' once, 'Healty' → 'Fever' twice
+	if val, _ := v.GetTransitionProbability(incStates[0], incStates[1]); math.Abs(val-2.0/3.0) > 1e-12 {
+		t.Error(
+			"Transition probability from 'Healty' to 'Fever' has to be 2/3, but got", val,
+		)
+	}
+	if _, ok := v.GetEmissionProbability(incStates[1], incomingObservations[0]); ok {
+		t.Error(
+			"Emission of 'normal' by 'Fever' has never been seen and has not to be set without smoothing",
+		)
+	}
+
+	for i := range incomingObservations {
+		v.AddObservation(incomingObservations[i])
+	}
+	if _, err := v.EvalPath(); err != nil {
+		t.Error(
+			"Fitted model has to be evaluated, but got", err,
+		)
+	}
+
+	smoothed := New()
+	if err := smoothed.Fit(pairs, 1); err != nil {
+		t.Fatal(err)
+	}
+	// (0 + 1) / (3 + 3)
+	if val, ok := smoothed.GetEmissionProbability(incStates[1], incomingObservations[0]); !ok || math.Abs(val-1.0/6.0) > 1e-12 {
+		t.Error(
+			"Smoothed emission of 'normal' by 'Fever' has to be 1/6, but got", val, ok,
+		)
+	}
+
+	pairs[0].States = pairs[0].States[:2]
+	if err := v.Fit(pairs, 0); !errors.Is(err, ErrPathLength) {
+		t.Error(
+			"Error has to be ErrPathLength, but got", err,
+		)
+	}
+}
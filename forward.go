@@ -0,0 +1,158 @@
+package viterbi
+
+import (
+	"math"
+)
+
+// Forward evaluates total probability of observations sequence for given model (sum over all possible paths, end probabilities included)
+// https://en.wikipedia.org/wiki/Forward_algorithm
+// When every probability is in [0;1]
+func (v *Viterbi) Forward() (float64, error) {
+	alpha, err := v.forward(false)
+	if err != nil {
+		return 0, err
+	}
+	return v.totalProbability(alpha, false)
+}
+
+// ForwardLog is the same as Forward, but when every probability is logarithmic.
+// Sums are evaluated via log-sum-exp in order to prevent underflow
+func (v *Viterbi) ForwardLog() (float64, error) {
+	alpha, err := v.forward(true)
+	if err != nil {
+		return math.Inf(-1), err
+	}
+	return v.totalProbability(alpha, true)
+}
+
+// totalProbability sums forward variables of the last observation combined with end probabilities
+func (v *Viterbi) totalProbability(alpha []map[State]float64, logSpace bool) (float64, error) {
+	last := alpha[len(alpha)-1]
+	vals := make([]float64, 0, len(last))
+	for _, st := range v.states {
+		prob, ok := last[st]
+		if !ok {
+			continue
+		}
+		endProb, err := v.endProbability(st, logSpace)
+		if err != nil {
+			return zero(logSpace), err
+		}
+		vals = append(vals, combine(logSpace, prob, endProb))
+	}
+	return sumProbabilities(logSpace, vals...), nil
+}
+
+// forward evaluates forward variables α (probability of observations up to t and being in the state at t) for every observation
+func (v *Viterbi) forward(logSpace bool) ([]map[State]float64, error) {
+	return v.forwardUntil(logSpace, len(v.observations)-1)
+}
+
+// forwardUntil is the same as forward, but evaluates forward variables of observations up to last one only (the rest are nil)
+func (v *Viterbi) forwardUntil(logSpace bool, last int) ([]map[State]float64, error) {
+	if err := v.validate(); err != nil {
+		return nil, err
+	}
+
+	alpha := make([]map[State]float64, len(v.observations))
+	alpha[0] = make(map[State]float64)
+	unknown := v.unknownAt(0)
+	for _, st := range v.states {
+		prob, ok, err := v.initialProbability(st, unknown, logSpace)
+		if err != nil {
+			return nil, err
+		}
+		if !ok {
+			continue
+		}
+		alpha[0][st] = prob
+	}
+	if len(alpha[0]) == 0 {
+		return nil, v.pathBroken(0)
+	}
+
+	for t := 1; t <= last; t++ {
+		alpha[t] = make(map[State]float64)
+		unknown := v.unknownAt(t)
+		for _, s := range v.states {
+			emissionProb, ok, err := v.emissionProbability(s, t, unknown, logSpace)
+			if err != nil {
+				return nil, err
+			}
+			if !ok {
+				// No emission for current state of current observation
+				continue
+			}
+			incoming := []float64{}
+			for _, r := range v.predecessorsOf(s) {
+				stateProb, ok := alpha[t-1][r]
+				if !ok {
+					continue
+				}
+				transitionProb, ok, err := v.transitionProbability(r, s, logSpace)
+				if err != nil {
+					return nil, err
+				}
+				if !ok {
+					continue
+				}
+				incoming = append(incoming, combine(logSpace, stateProb, transitionProb))
+			}
+			if len(incoming) == 0 {
+				// State is unreachable from any state of previous observation
+				continue
+			}
+			prob := combine(logSpace, sumProbabilities(logSpace, incoming...), emissionProb)
+			if impossible(logSpace, prob) {
+				continue
+			}
+			alpha[t][s] = prob
+		}
+		if len(alpha[t]) == 0 {
+			return nil, v.pathBroken(t)
+		}
+	}
+	return alpha, nil
+}
+
+// sumProbabilities sums classic probabilities or logarithmic ones (via log-sum-exp)
+func sumProbabilities(logSpace bool, vals ...float64) float64 {
+	if logSpace {
+		return LogSumExp(vals...)
+	}
+	sum := 0.0
+	for _, val := range vals {
+		sum += val
+	}
+	return sum
+}
+
+// LogSumExp evaluates log(exp(x1) + exp(x2) + ...) without underflow, the same way Forward does it for logarithmic probabilities.
+// Values of -Inf (impossible) are ignored; result is -Inf when every value is -Inf or there are no values
+func LogSumExp(vals ...float64) float64 {
+	maxVal := math.Inf(-1)
+	for _, val := range vals {
+		if val > maxVal {
+			maxVal = val
+		}
+	}
+	if math.IsInf(maxVal, 0) {
+		return maxVal
+	}
+	sum := 0.0
+	for _, val := range vals {
+		sum += math.Exp(val - maxVal)
+	}
+	return maxVal + math.Log(sum)
+}
+
+// columnValues returns values of the trellis column in order of states (so sums do not depend on map iteration order)
+func (v *Viterbi) columnValues(column map[State]float64) []float64 {
+	vals := make([]float64, 0, len(column))
+	for _, st := range v.states {
+		if val, ok := column[st]; ok {
+			vals = append(vals, val)
+		}
+	}
+	return vals
+}
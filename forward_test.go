@@ -0,0 +1,157 @@
+package viterbi
+
+import (
+	"math"
+	"testing"
+)
+
+// logModel returns copy of the model with logarithmic probabilities
+func logModel(v *Viterbi) *Viterbi {
+	logV := New()
+	for _, st := range v.states {
+		logV.AddState(st)
+	}
+	for _, obs := range v.observations {
+		logV.AddObservation(obs)
+	}
+	for _, st := range v.states {
+		if val, ok := v.GetStartProbability(st); ok {
+			logV.PutStartProbability(st, math.Log(val))
+		}
+		for _, obs := range v.observations {
+			if val, ok := v.GetEmissionProbability(st, obs); ok {
+				logV.PutEmissionProbability(st, obs, math.Log(val))
+			}
+		}
+		for _, to := range v.states {
+			if val, ok := v.GetTransitionProbability(st, to); ok {
+				logV.PutTransitionProbability(st, to, math.Log(val))
+			}
+		}
+	}
+	return logV
+}
+
+func TestViterbiForward(t *testing.T) {
+	v, incStates, _ := healthModel()
+
+	expected := 0.0
+	for _, s0 := range incStates {
+		for _, s1 := range incStates {
+			for _, s2 := range incStates {
+				prob, err := v.ScorePath([]State{s0, s1, s2})
+				if err != nil {
+					t.Fatal(err)
+				}
+				expected += prob
+			}
+		}
+	}
+
+	prob, err := v.Forward()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if math.Abs(prob-expected) > 1e-12 {
+		t.Error(
+			"Forward probability has to be", expected, "but got", prob,
+		)
+	}
+
+	logProb, err := logModel(v).ForwardLog()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if math.Abs(logProb-math.Log(expected)) > 1e-12 {
+		t.Error(
+			"Logarithmic forward probability has to be", math.Log(expected), "but got", logProb,
+		)
+	}
+
+	if _, err := New().Forward(); err != ErrNoStates {
+		t.Error(
+			"Error has to be ErrNoStates, but got", err,
+		)
+	}
+	empty := New()
+	empty.AddState(incStates[0])
+	if _, err := empty.ForwardLog(); err != ErrNoObservations {
+		t.Error(
+			"Error has to be ErrNoObservations, but got", err,
+		)
+	}
+}
+
+func TestViterbiForwardEndProbabilities(t *testing.T) {
+	v, incStates, _ := healthModel()
+	v.PutEndProbability(incStates[0], 0.1)
+	v.PutEndProbability(incStates[1], 0.9)
+
+	// Sum over every path scored with end probability, and posterior of the last observation
+	total, last := 0.0, make(map[State]float64)
+	for _, a := range incStates {
+		for _, b := range incStates {
+			for _, c := range incStates {
+				prob, err := v.ScorePath([]State{a, b, c})
+				if err != nil {
+					t.Fatal(err)
+				}
+				total += prob
+				last[c] += prob
+			}
+		}
+	}
+	forward, err := v.Forward()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if math.Abs(forward-total) > 1e-12 {
+		t.Error(
+			"Total probability has to be", total, ", but got", forward,
+		)
+	}
+	logForward, err := v.ToLog().ForwardLog()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if math.Abs(logForward-math.Log(total)) > 1e-12 {
+		t.Error(
+			"Logarithmic total probability has to be", math.Log(total), ", but got", logForward,
+		)
+	}
+	posterior, err := v.PosteriorAt(2)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, st := range incStates {
+		if math.Abs(posterior[st]-last[st]/total) > 1e-12 {
+			t.Error(
+				"Posterior of", st, "has to be", last[st]/total, ", but got", posterior[st],
+			)
+		}
+	}
+}
+
+func TestLogSumExp(t *testing.T) {
+	if val := LogSumExp(math.Log(0.2), math.Log(0.3), math.Inf(-1)); math.Abs(val-math.Log(0.5)) > 1e-12 {
+		t.Error(
+			"Sum has to be", math.Log(0.5), "but got", val,
+		)
+	}
+	// Large magnitudes do not underflow
+	if val := LogSumExp(-1000, -1000); math.Abs(val-(-1000+math.Log(2))) > 1e-9 {
+		t.Error(
+			"Sum has to be", -1000+math.Log(2), "but got", val,
+		)
+	}
+	if val := LogSumExp(math.Inf(-1), math.Inf(-1)); !math.IsInf(val, -1) {
+		t.Error(
+			"Sum of impossible values has to be -Inf, but got", val,
+		)
+	}
+	if val := LogSumExp(); !math.IsInf(val, -1) {
+		t.Error(
+			"Sum of no values has to be -Inf, but got", val,
+		)
+	}
+}
@@ -0,0 +1,214 @@
+package viterbi
+
+import (
+	"fmt"
+	"math"
+)
+
+// ViterbiG is type-parameterized variant of Viterbi.
+// States and observations are stored as concrete comparable types, so there is no interface boxing in probabilities maps.
+// Since states have no ID(), equal probabilities are resolved in favour of the state which has been added first
+type ViterbiG[S comparable, O comparable] struct {
+	states                  []S
+	observations            []O
+	startProbabilities      map[S]float64
+	emissionProbabilities   map[EmissionHashG[S, O]]float64
+	transitionProbabilities map[TransitionHashG[S]]float64
+}
+
+// TransitionHashG is key for transition probabilities of ViterbiG
+type TransitionHashG[S comparable] struct {
+	From S
+	To   S
+}
+
+// EmissionHashG is key for emission probabilities of ViterbiG
+type EmissionHashG[S comparable, O comparable] struct {
+	State       S
+	Observation O
+}
+
+// ViterbiPathG is evaluation result of ViterbiG
+type ViterbiPathG[S comparable] struct {
+	Probability float64
+	Path        []S
+}
+
+type viterbiValG[S comparable] struct {
+	prob float64
+	prev S
+}
+
+// NewG returns empty ViterbiG
+func NewG[S comparable, O comparable]() *ViterbiG[S, O] {
+	return &ViterbiG[S, O]{
+		startProbabilities:      make(map[S]float64),
+		emissionProbabilities:   make(map[EmissionHashG[S, O]]float64),
+		transitionProbabilities: make(map[TransitionHashG[S]]float64),
+	}
+}
+
+func (v *ViterbiG[S, O]) AddState(s S) {
+	v.states = append(v.states, s)
+}
+
+func (v *ViterbiG[S, O]) AddObservation(obs O) {
+	v.observations = append(v.observations, obs)
+}
+
+func (v *ViterbiG[S, O]) PutStartProbability(state S, val float64) {
+	if v.startProbabilities == nil {
+		v.startProbabilities = make(map[S]float64)
+	}
+	v.startProbabilities[state] = val
+}
+
+func (v *ViterbiG[S, O]) PutEmissionProbability(s S, obs O, val float64) {
+	if v.emissionProbabilities == nil {
+		v.emissionProbabilities = make(map[EmissionHashG[S, O]]float64)
+	}
+	emKey := EmissionHashG[S, O]{s, obs}
+	if _, ok := v.emissionProbabilities[emKey]; !ok {
+		v.emissionProbabilities[emKey] = val
+	}
+}
+
+func (v *ViterbiG[S, O]) PutTransitionProbability(f S, t S, val float64) {
+	if v.transitionProbabilities == nil {
+		v.transitionProbabilities = make(map[TransitionHashG[S]]float64)
+	}
+	trKey := TransitionHashG[S]{f, t}
+	if _, ok := v.transitionProbabilities[trKey]; !ok {
+		v.transitionProbabilities[trKey] = val
+	}
+}
+
+// EvalPath is the same as Viterbi.EvalPath
+// When every probability is in [0;1]
+func (v *ViterbiG[S, O]) EvalPath() (ViterbiPathG[S], error) {
+	return v.evalPath(false)
+}
+
+// EvalPathLogProbabilities is the same as Viterbi.EvalPathLogProbabilities
+// When every probability is logarithmic
+func (v *ViterbiG[S, O]) EvalPathLogProbabilities() (ViterbiPathG[S], error) {
+	return v.evalPath(true)
+}
+
+func (v *ViterbiG[S, O]) evalPath(logSpace bool) (ViterbiPathG[S], error) {
+	if len(v.states) == 0 {
+		return ViterbiPathG[S]{}, ErrNoStates
+	}
+	if len(v.observations) == 0 {
+		return ViterbiPathG[S]{}, ErrNoObservations
+	}
+
+	V := make([]map[S]viterbiValG[S], len(v.observations))
+	V[0] = make(map[S]viterbiValG[S])
+	for _, st := range v.states {
+		startProb, ok := v.startProbabilities[st]
+		if !ok {
+			continue
+		}
+		if !validProbability(logSpace, startProb) {
+			return ViterbiPathG[S]{}, fmt.Errorf("%w: start probability %v of state %v", ErrInvalidProbability, startProb, st)
+		}
+		emissionProb, ok, err := v.emissionProbability(st, 0, logSpace)
+		if err != nil {
+			return ViterbiPathG[S]{}, err
+		}
+		if !ok {
+			continue
+		}
+		prob := combine(logSpace, startProb, emissionProb)
+		if impossible(logSpace, prob) {
+			continue
+		}
+		V[0][st] = viterbiValG[S]{prob: prob}
+	}
+	if len(V[0]) == 0 {
+		return ViterbiPathG[S]{}, ErrPathBroken
+	}
+
+	for t := 1; t < len(v.observations); t++ {
+		V[t] = make(map[S]viterbiValG[S])
+		for _, s := range v.states {
+			emissionProb, ok, err := v.emissionProbability(s, t, logSpace)
+			if err != nil {
+				return ViterbiPathG[S]{}, err
+			}
+			if !ok {
+				// No emission for current state of current observation
+				continue
+			}
+			best := viterbiValG[S]{}
+			found := false
+			for _, r := range v.states {
+				stateProb, ok := V[t-1][r]
+				if !ok {
+					// No probability from state to observation
+					continue
+				}
+				transitionProb, ok := v.transitionProbabilities[TransitionHashG[S]{r, s}]
+				if !ok {
+					// No transition between states
+					continue
+				}
+				if !validProbability(logSpace, transitionProb) {
+					return ViterbiPathG[S]{}, fmt.Errorf("%w: transition probability %v from state %v to state %v", ErrInvalidProbability, transitionProb, r, s)
+				}
+				prob := combine(logSpace, stateProb.prob, transitionProb)
+				if !found || prob > best.prob {
+					best = viterbiValG[S]{prob: prob, prev: r}
+					found = true
+				}
+			}
+			if !found {
+				// State is unreachable from any state of previous observation
+				continue
+			}
+			prob := combine(logSpace, best.prob, emissionProb)
+			if impossible(logSpace, prob) {
+				continue
+			}
+			V[t][s] = viterbiValG[S]{prob: prob, prev: best.prev}
+		}
+		if len(V[t]) == 0 {
+			return ViterbiPathG[S]{}, ErrPathBroken
+		}
+	}
+
+	maxPr := -math.MaxFloat64
+	var previous S
+	found := false
+	for _, st := range v.states {
+		value, ok := V[len(V)-1][st]
+		if !ok {
+			continue
+		}
+		if !found || value.prob > maxPr {
+			maxPr = value.prob
+			previous = st
+			found = true
+		}
+	}
+
+	opt := make([]S, len(V))
+	for t := len(V) - 1; t >= 0; t-- {
+		opt[t] = previous
+		previous = V[t][previous].prev
+	}
+	return ViterbiPathG[S]{maxPr, opt}, nil
+}
+
+// emissionProbability returns probability of the state to emit observation with index t
+func (v *ViterbiG[S, O]) emissionProbability(s S, t int, logSpace bool) (float64, bool, error) {
+	emissionProb, ok := v.emissionProbabilities[EmissionHashG[S, O]{s, v.observations[t]}]
+	if !ok {
+		return 0, false, nil
+	}
+	if !validProbability(logSpace, emissionProb) {
+		return 0, false, fmt.Errorf("%w: emission probability %v of state %v for observation %v", ErrInvalidProbability, emissionProb, s, v.observations[t])
+	}
+	return emissionProb, true, nil
+}
@@ -0,0 +1,67 @@
+package viterbi
+
+import (
+	"testing"
+)
+
+func TestViterbiGEvalPath(t *testing.T) {
+	const (
+		healthy = iota
+		fever
+	)
+	const (
+		normal = iota
+		cold
+		dizzy
+	)
+	v := NewG[int, int]()
+	v.AddState(healthy)
+	v.AddState(fever)
+	v.AddObservation(normal)
+	v.AddObservation(cold)
+	v.AddObservation(dizzy)
+
+	v.PutStartProbability(healthy, 0.6)
+	v.PutStartProbability(fever, 0.4)
+
+	v.PutEmissionProbability(healthy, normal, 0.5)
+	v.PutEmissionProbability(healthy, cold, 0.4)
+	v.PutEmissionProbability(healthy, dizzy, 0.1)
+	v.PutEmissionProbability(fever, normal, 0.1)
+	v.PutEmissionProbability(fever, cold, 0.3)
+	v.PutEmissionProbability(fever, dizzy, 0.6)
+
+	v.PutTransitionProbability(healthy, healthy, 0.7)
+	v.PutTransitionProbability(healthy, fever, 0.3)
+	v.PutTransitionProbability(fever, healthy, 0.4)
+	v.PutTransitionProbability(fever, fever, 0.6)
+
+	vpath, err := v.EvalPath()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if vpath.Probability != 0.01512 {
+		t.Error(
+			"Probability has to be 0.01512, but got", vpath.Probability,
+		)
+	}
+	expected := []int{healthy, healthy, fever}
+	if len(vpath.Path) != len(expected) {
+		t.Fatal(
+			"Expected 3 states, but got:", len(vpath.Path),
+		)
+	}
+	for i := range expected {
+		if vpath.Path[i] != expected[i] {
+			t.Error(
+				"State", i, "has to be", expected[i], "but got", vpath.Path[i],
+			)
+		}
+	}
+
+	if _, err := NewG[int, int]().EvalPath(); err != ErrNoStates {
+		t.Error(
+			"Error has to be ErrNoStates, but got", err,
+		)
+	}
+}
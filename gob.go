@@ -0,0 +1,22 @@
+package viterbi
+
+import (
+	"encoding/gob"
+	"io"
+)
+
+// WriteGob serializes states, observations, every probability, forbidden transitions and transition orientation of the model into w via encoding/gob.
+// It's binary (and more compact) alternative to ToJSON: everything is keyed by ID() as well
+func (v *Viterbi) WriteGob(w io.Writer) error {
+	return gob.NewEncoder(w).Encode(v.toSerialized())
+}
+
+// ReadGob restores model serialized by WriteGob.
+// Caller has to provide functions reconstructing states and observations by their ID()
+func ReadGob(r io.Reader, stateByID func(int) State, obsByID func(int) Observation) (*Viterbi, error) {
+	model := serializedModel{}
+	if err := gob.NewDecoder(r).Decode(&model); err != nil {
+		return nil, err
+	}
+	return fromSerialized(model, stateByID, obsByID)
+}
@@ -0,0 +1,169 @@
+package viterbi
+
+import (
+	"bytes"
+	"errors"
+	"math"
+	"testing"
+)
+
+func TestViterbiGob(t *testing.T) {
+	v, incStates, incomingObservations := healthModel()
+	v.PutEndProbability(incStates[0], 0.9)
+	expected, err := v.EvalPath()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var buf bytes.Buffer
+	if err := v.WriteGob(&buf); err != nil {
+		t.Fatal(err)
+	}
+	stateByID := func(id int) State {
+		for i := range incStates {
+			if incStates[i].ID() == id {
+				return incStates[i]
+			}
+		}
+		return nil
+	}
+	obsByID := func(id int) Observation {
+		for i := range incomingObservations {
+			if incomingObservations[i].ID() == id {
+				return incomingObservations[i]
+			}
+		}
+		return nil
+	}
+	data := append([]byte{}, buf.Bytes()...)
+	restored, err := ReadGob(bytes.NewReader(data), stateByID, obsByID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for i := range incomingObservations {
+		if restored.observations[i].ID() != incomingObservations[i].ID() {
+			t.Error(
+				"Observation on position", i, "has to be", incomingObservations[i], ", but got", restored.observations[i],
+			)
+		}
+	}
+	vpath, err := restored.EvalPath()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if vpath.Probability != expected.Probability {
+		t.Error(
+			"Probability has to be", expected.Probability, ", but got", vpath.Probability,
+		)
+	}
+	for i := range expected.Path {
+		if vpath.Path[i] != expected.Path[i] {
+			t.Error(
+				"State on position", i, "has to be", expected.Path[i], ", but got", vpath.Path[i],
+			)
+		}
+	}
+
+	// Logarithmic model contains -Inf
+	logV := v.ToLog()
+	logV.PutTransitionProbability(incStates[0], incStates[0], math.Inf(-1))
+	buf.Reset()
+	if err := logV.WriteGob(&buf); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := ReadGob(&buf, stateByID, obsByID); err != nil {
+		t.Error(
+			"Model with infinite probabilities has to be restored, but got", err,
+		)
+	}
+
+	if _, err := ReadGob(bytes.NewReader(data), func(int) State { return nil }, obsByID); !errors.Is(err, ErrUnknownState) {
+		t.Error(
+			"Error has to be ErrUnknownState, but got", err,
+		)
+	}
+}
+
+func TestViterbiGobSecondOrderAndDurations(t *testing.T) {
+	v, incStates, incomingObservations := healthModel()
+	v.PutTransitionProbability2(incStates[0], incStates[1], incStates[0], 0.8)
+	v.SetDurationProbability(incStates[1], 2, 0.25)
+
+	var buf bytes.Buffer
+	if err := v.WriteGob(&buf); err != nil {
+		t.Fatal(err)
+	}
+	stateByID := func(id int) State {
+		for i := range incStates {
+			if incStates[i].ID() == id {
+				return incStates[i]
+			}
+		}
+		return nil
+	}
+	obsByID := func(id int) Observation {
+		for i := range incomingObservations {
+			if incomingObservations[i].ID() == id {
+				return incomingObservations[i]
+			}
+		}
+		return nil
+	}
+	restored, err := ReadGob(&buf, stateByID, obsByID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if val, ok := restored.transitionProbabilities2[TransitionHash2{incStates[0].ID(), incStates[1].ID(), incStates[0].ID()}]; !ok || val != 0.8 || len(restored.transitionProbabilities2) != 1 {
+		t.Error(
+			"Second-order transitions have to be restored, but got", restored.transitionProbabilities2,
+		)
+	}
+	if val, ok := restored.durationProbabilities[DurationHash{incStates[1].ID(), 2}]; !ok || val != 0.25 || len(restored.durationProbabilities) != 1 {
+		t.Error(
+			"Duration probabilities have to be restored, but got", restored.durationProbabilities,
+		)
+	}
+}
+
+func TestViterbiGobForbiddenTransitions(t *testing.T) {
+	v, incStates, incomingObservations := healthModel()
+	v.ForbidTransition(incStates[0], incStates[0])
+	expected, err := v.EvalPath()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var buf bytes.Buffer
+	if err := v.WriteGob(&buf); err != nil {
+		t.Fatal(err)
+	}
+	stateByID := func(id int) State {
+		for i := range incStates {
+			if incStates[i].ID() == id {
+				return incStates[i]
+			}
+		}
+		return nil
+	}
+	obsByID := func(id int) Observation {
+		for i := range incomingObservations {
+			if incomingObservations[i].ID() == id {
+				return incomingObservations[i]
+			}
+		}
+		return nil
+	}
+	restored, err := ReadGob(&buf, stateByID, obsByID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	vpath, err := restored.EvalPath()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if vpath.String() != expected.String() {
+		t.Error(
+			"Path has to be", expected, ", but got", vpath,
+		)
+	}
+}
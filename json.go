@@ -0,0 +1,52 @@
+package viterbi
+
+import (
+	"encoding/json"
+	"math"
+	"strconv"
+)
+
+// ToJSON serializes states, observations, every probability, forbidden transitions and transition orientation of the model.
+// Since State and Observation are interfaces, everything is keyed by ID()
+func (v *Viterbi) ToJSON() ([]byte, error) {
+	return json.Marshal(v.toSerialized())
+}
+
+// FromJSON restores model serialized by ToJSON.
+// Caller has to provide functions reconstructing states and observations by their ID()
+func FromJSON(data []byte, stateByID func(int) State, obsByID func(int) Observation) (*Viterbi, error) {
+	model := serializedModel{}
+	if err := json.Unmarshal(data, &model); err != nil {
+		return nil, err
+	}
+	return fromSerialized(model, stateByID, obsByID)
+}
+
+// storedFloat is float64 which supports infinities and NaN in JSON (logarithmic probabilities could be -Inf)
+type storedFloat float64
+
+func (f storedFloat) MarshalJSON() ([]byte, error) {
+	val := float64(f)
+	if math.IsInf(val, 0) || math.IsNaN(val) {
+		return json.Marshal(strconv.FormatFloat(val, 'g', -1, 64))
+	}
+	return json.Marshal(val)
+}
+
+func (f *storedFloat) UnmarshalJSON(data []byte) error {
+	var str string
+	if err := json.Unmarshal(data, &str); err == nil {
+		val, err := strconv.ParseFloat(str, 64)
+		if err != nil {
+			return err
+		}
+		*f = storedFloat(val)
+		return nil
+	}
+	var val float64
+	if err := json.Unmarshal(data, &val); err != nil {
+		return err
+	}
+	*f = storedFloat(val)
+	return nil
+}
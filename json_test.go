@@ -0,0 +1,219 @@
+package viterbi
+
+import (
+	"math"
+	"testing"
+)
+
+func TestViterbiJSON(t *testing.T) {
+	v, incStates, incomingObservations := healthModel()
+	expected, err := v.EvalPath()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	data, err := v.ToJSON()
+	if err != nil {
+		t.Fatal(err)
+	}
+	stateByID := func(id int) State {
+		for i := range incStates {
+			if incStates[i].ID() == id {
+				return incStates[i]
+			}
+		}
+		return nil
+	}
+	obsByID := func(id int) Observation {
+		for i := range incomingObservations {
+			if incomingObservations[i].ID() == id {
+				return incomingObservations[i]
+			}
+		}
+		return nil
+	}
+	restored, err := FromJSON(data, stateByID, obsByID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	vpath, err := restored.EvalPath()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if vpath.Probability != expected.Probability {
+		t.Error(
+			"Probability has to be", expected.Probability, "but got", vpath.Probability,
+		)
+	}
+	for i := range expected.Path {
+		if vpath.Path[i] != expected.Path[i] {
+			t.Error(
+				"State", i, "has to be", expected.Path[i], "but got", vpath.Path[i],
+			)
+		}
+	}
+
+	// Logarithmic model could contain infinities
+	logV := logModel(v)
+	logV.PutTransitionProbability(incStates[0], CustomState{Name: "Unreachable", id: 3}, math.Inf(-1))
+	data, err = logV.ToJSON()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := FromJSON(data, stateByID, obsByID); err == nil {
+		t.Error(
+			"Error has to be returned for state which can't be reconstructed",
+		)
+	}
+	restored, err = FromJSON(data, func(id int) State {
+		if id == 3 {
+			return CustomState{Name: "Unreachable", id: 3}
+		}
+		return stateByID(id)
+	}, obsByID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if val, _ := restored.GetTransitionProbability(incStates[0], CustomState{Name: "Unreachable", id: 3}); !math.IsInf(val, -1) {
+		t.Error(
+			"Transition probability has to be -Inf, but got", val,
+		)
+	}
+}
+
+func TestViterbiJSONSecondOrderAndDurations(t *testing.T) {
+	v, incStates, incomingObservations := healthModel()
+	v.PutTransitionProbability2(incStates[0], incStates[1], incStates[0], 0.8)
+	v.SetDurationProbability(incStates[1], 2, 0.25)
+
+	data, err := v.ToJSON()
+	if err != nil {
+		t.Fatal(err)
+	}
+	stateByID := func(id int) State {
+		for i := range incStates {
+			if incStates[i].ID() == id {
+				return incStates[i]
+			}
+		}
+		return nil
+	}
+	obsByID := func(id int) Observation {
+		for i := range incomingObservations {
+			if incomingObservations[i].ID() == id {
+				return incomingObservations[i]
+			}
+		}
+		return nil
+	}
+	restored, err := FromJSON(data, stateByID, obsByID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if val, ok := restored.transitionProbabilities2[TransitionHash2{incStates[0].ID(), incStates[1].ID(), incStates[0].ID()}]; !ok || val != 0.8 || len(restored.transitionProbabilities2) != 1 {
+		t.Error(
+			"Second-order transitions have to be restored, but got", restored.transitionProbabilities2,
+		)
+	}
+	if val, ok := restored.durationProbabilities[DurationHash{incStates[1].ID(), 2}]; !ok || val != 0.25 || len(restored.durationProbabilities) != 1 {
+		t.Error(
+			"Duration probabilities have to be restored, but got", restored.durationProbabilities,
+		)
+	}
+}
+
+func TestViterbiJSONForbiddenTransitions(t *testing.T) {
+	v, incStates, incomingObservations := healthModel()
+	v.ForbidTransition(incStates[0], incStates[0])
+	expected, err := v.EvalPath()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	data, err := v.ToJSON()
+	if err != nil {
+		t.Fatal(err)
+	}
+	stateByID := func(id int) State {
+		for i := range incStates {
+			if incStates[i].ID() == id {
+				return incStates[i]
+			}
+		}
+		return nil
+	}
+	obsByID := func(id int) Observation {
+		for i := range incomingObservations {
+			if incomingObservations[i].ID() == id {
+				return incomingObservations[i]
+			}
+		}
+		return nil
+	}
+	restored, err := FromJSON(data, stateByID, obsByID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	vpath, err := restored.EvalPath()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if vpath.String() != expected.String() {
+		t.Error(
+			"Path has to be", expected, ", but got", vpath,
+		)
+	}
+}
+
+func TestViterbiJSONTransitionOrientation(t *testing.T) {
+	v, incStates, incomingObservations := healthModel()
+	expected, err := v.EvalPath()
+	if err != nil {
+		t.Fatal(err)
+	}
+	transposed := make(map[TransitionHash]float64, len(v.transitionProbabilities))
+	for key, val := range v.transitionProbabilities {
+		transposed[TransitionHash{From: key.To, To: key.From}] = val
+	}
+	v.transitionProbabilities = transposed
+	v.SetTransitionOrientation(TransitionToFrom)
+
+	data, err := v.ToJSON()
+	if err != nil {
+		t.Fatal(err)
+	}
+	stateByID := func(id int) State {
+		for i := range incStates {
+			if incStates[i].ID() == id {
+				return incStates[i]
+			}
+		}
+		return nil
+	}
+	obsByID := func(id int) Observation {
+		for i := range incomingObservations {
+			if incomingObservations[i].ID() == id {
+				return incomingObservations[i]
+			}
+		}
+		return nil
+	}
+	restored, err := FromJSON(data, stateByID, obsByID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if restored.transitionOrientation != TransitionToFrom {
+		t.Error(
+			"Orientation has to be TransitionToFrom, but got", restored.transitionOrientation,
+		)
+	}
+	vpath, err := restored.EvalPath()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if vpath.String() != expected.String() {
+		t.Error(
+			"Path has to be", expected, ", but got", vpath,
+		)
+	}
+}
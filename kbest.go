@@ -0,0 +1,252 @@
+package viterbi
+
+import (
+	"errors"
+	"math"
+	"sort"
+)
+
+// ErrInvalidPathsNumber is returned when requested number of paths is not positive
+var ErrInvalidPathsNumber = errors.New("number of paths has to be positive")
+
+// viterbiValN is single entry of list Viterbi trellis cell
+type viterbiValN struct {
+	prob     float64
+	prev     State
+	prevRank int
+}
+
+// latticeN is list Viterbi trellis: every cell keeps up to k best partial paths ending in the state
+type latticeN struct {
+	V []map[State][]viterbiValN
+	k int
+	// logScale is logarithm of accumulated scaling factor of classic probabilities (see rescaleColumnN)
+	logScale float64
+}
+
+// EvalPathN evaluates up to k most probable distinct paths ordered by descending probability.
+// It uses parallel list Viterbi algorithm: every trellis cell keeps k best partial paths
+// https://en.wikipedia.org/wiki/List_Viterbi_algorithm
+// When every probability is in [0;1]
+// Equal probabilities are resolved according to the tie-breaker (see SetTieBreaker), in favour of the state with the lowest ID() by default
+func (v *Viterbi) EvalPathN(k int) ([]ViterbiPath, error) {
+	return v.evalPathN(k, false)
+}
+
+// EvalPathNLogProbabilities is the same as EvalPathN, but when every probability is logarithmic
+func (v *Viterbi) EvalPathNLogProbabilities(k int) ([]ViterbiPath, error) {
+	return v.evalPathN(k, true)
+}
+
+// EvalPathWithMargin evaluates the most probable path together with confidence margin: difference of logarithmic probabilities
+// of the best and the second best paths (see EvalPathN). Margin is +Inf when there is no other valid path
+// When every probability is in [0;1]
+func (v *Viterbi) EvalPathWithMargin() (ViterbiPath, float64, error) {
+	return v.evalPathWithMargin(false)
+}
+
+// EvalPathWithMarginLogProbabilities is the same as EvalPathWithMargin, but when every probability is logarithmic
+func (v *Viterbi) EvalPathWithMarginLogProbabilities() (ViterbiPath, float64, error) {
+	return v.evalPathWithMargin(true)
+}
+
+func (v *Viterbi) evalPathWithMargin(logSpace bool) (ViterbiPath, float64, error) {
+	paths, err := v.evalPathN(2, logSpace)
+	if err != nil {
+		return ViterbiPath{}, 0, err
+	}
+	if len(paths) < 2 {
+		return paths[0], math.Inf(1), nil
+	}
+	return paths[0], paths[0].LogProbability - paths[1].LogProbability, nil
+}
+
+func (v *Viterbi) evalPathN(k int, logSpace bool) ([]ViterbiPath, error) {
+	if k < 1 {
+		return nil, ErrInvalidPathsNumber
+	}
+	lattice, err := v.evalLatticeN(k, logSpace)
+	if err != nil {
+		return nil, err
+	}
+	return v.pathsN(lattice, k, logSpace)
+}
+
+// evalLatticeN evaluates list trellis keeping k best partial paths in every cell
+func (v *Viterbi) evalLatticeN(k int, logSpace bool) (latticeN, error) {
+	if err := v.validate(); err != nil {
+		return latticeN{}, err
+	}
+
+	V := make([]map[State][]viterbiValN, len(v.observations))
+	V[0] = make(map[State][]viterbiValN)
+	unknown := v.unknownAt(0)
+	for _, st := range v.states {
+		prob, ok, err := v.initialProbability(st, unknown, logSpace)
+		if err != nil {
+			return latticeN{}, err
+		}
+		if !ok {
+			continue
+		}
+		V[0][st] = []viterbiValN{{prob: prob}}
+	}
+	if len(V[0]) == 0 {
+		return latticeN{}, v.pathBroken(0)
+	}
+	logScale := 0.0
+	if !logSpace {
+		logScale += v.rescaleColumnN(V[0])
+	}
+
+	for t := 1; t < len(v.observations); t++ {
+		V[t] = make(map[State][]viterbiValN)
+		unknown := v.unknownAt(t)
+		for _, s := range v.states {
+			emissionProb, ok, err := v.emissionProbability(s, t, unknown, logSpace)
+			if err != nil {
+				return latticeN{}, err
+			}
+			if !ok {
+				// No emission for current state of current observation
+				continue
+			}
+			candidates := []viterbiValN{}
+			for _, r := range v.predecessorsOf(s) {
+				entries, ok := V[t-1][r]
+				if !ok {
+					// No probability from state to observation
+					continue
+				}
+				transitionProb, ok, err := v.transitionProbability(r, s, logSpace)
+				if err != nil {
+					return latticeN{}, err
+				}
+				if !ok {
+					// No transition between states
+					continue
+				}
+				for rank := range entries {
+					prob := combine(logSpace, combine(logSpace, entries[rank].prob, transitionProb), emissionProb)
+					if impossible(logSpace, prob) {
+						continue
+					}
+					candidates = append(candidates, viterbiValN{prob: prob, prev: r, prevRank: rank})
+				}
+			}
+			if len(candidates) == 0 {
+				// State is unreachable from any state of previous observation
+				continue
+			}
+			sort.Slice(candidates, func(i, j int) bool {
+				return v.preferEntry(candidates[i], candidates[j])
+			})
+			if len(candidates) > k {
+				candidates = candidates[:k]
+			}
+			V[t][s] = candidates
+		}
+		if len(V[t]) == 0 {
+			return latticeN{}, v.pathBroken(t)
+		}
+		if !logSpace {
+			logScale += v.rescaleColumnN(V[t])
+		}
+	}
+	return latticeN{V: V, k: k, logScale: logScale}, nil
+}
+
+// pathsN restores up to k most probable distinct paths from list trellis (k is not greater than the one trellis has been evaluated for)
+func (v *Viterbi) pathsN(lattice latticeN, k int, logSpace bool) ([]ViterbiPath, error) {
+	V, logScale := lattice.V, lattice.logScale
+
+	// Collect complete paths ending in every state of the last observation
+	type ending struct {
+		state State
+		rank  int
+		prob  float64
+	}
+	endings := []ending{}
+	for st, entries := range V[len(V)-1] {
+		endProb, err := v.endProbability(st, logSpace)
+		if err != nil {
+			return nil, err
+		}
+		for rank := range entries {
+			prob := combine(logSpace, entries[rank].prob, endProb)
+			if impossible(logSpace, prob) {
+				continue
+			}
+			endings = append(endings, ending{state: st, rank: rank, prob: prob})
+		}
+	}
+	if len(endings) == 0 {
+		return nil, ErrNoValidPath
+	}
+	sort.Slice(endings, func(i, j int) bool {
+		if endings[i].prob != endings[j].prob || endings[i].state.ID() != endings[j].state.ID() {
+			return v.preferState(endings[i].prob, endings[i].state, endings[j].prob, endings[j].state)
+		}
+		return endings[i].rank < endings[j].rank
+	})
+	if len(endings) > k {
+		endings = endings[:k]
+	}
+
+	paths := make([]ViterbiPath, 0, len(endings))
+	for _, e := range endings {
+		path := make([]State, len(V))
+		st, rank := e.state, e.rank
+		for t := len(V) - 1; t >= 0; t-- {
+			path[t] = st
+			entry := V[t][st][rank]
+			st, rank = entry.prev, entry.prevRank
+		}
+		vpath := ViterbiPath{Probability: e.prob, LogProbability: logProbability(logSpace, e.prob), Path: path, StepProbabilities: v.stepProbabilities(path, logSpace)}
+		if !logSpace {
+			vpath.Probability *= math.Exp(logScale)
+			vpath.LogProbability += logScale
+		}
+		paths = append(paths, vpath)
+	}
+	return paths, nil
+}
+
+// rescaleColumnN is the same as rescaleColumn, but for list trellis column: every entry of every cell is normalized by the same sum
+func (v *Viterbi) rescaleColumnN(column map[State][]viterbiValN) float64 {
+	sum := 0.0
+	for _, st := range v.states {
+		for _, entry := range column[st] {
+			sum += entry.prob
+		}
+	}
+	if !needsRescale(sum) {
+		return 0
+	}
+	for _, entries := range column {
+		for i := range entries {
+			entries[i].prob /= sum
+		}
+	}
+	return math.Log(sum)
+}
+
+// bestColumns returns trellis of the best entries of every cell: it's trellis of EvalPath (without pruning), so backtrack could be applied to it
+func (lattice latticeN) bestColumns() []map[State]ViterbiVal {
+	V := make([]map[State]ViterbiVal, len(lattice.V))
+	for t := range lattice.V {
+		V[t] = make(map[State]ViterbiVal, len(lattice.V[t]))
+		for st, entries := range lattice.V[t] {
+			V[t][st] = ViterbiVal{prob: entries[0].prob, prev: entries[0].prev}
+		}
+	}
+	return V
+}
+
+// preferEntry reports whether entry a has to be placed before entry b in trellis cell
+func (v *Viterbi) preferEntry(a, b viterbiValN) bool {
+	if a.prob != b.prob || a.prev.ID() != b.prev.ID() {
+		return v.preferState(a.prob, a.prev, b.prob, b.prev)
+	}
+	return a.prevRank < b.prevRank
+}
@@ -0,0 +1,165 @@
+package viterbi
+
+import (
+	"math"
+	"sort"
+	"testing"
+)
+
+func TestViterbiEvalPathN(t *testing.T) {
+	v, incStates, _ := healthModel()
+
+	// Enumerate every possible path to compare with list Viterbi output
+	expected := []float64{}
+	for _, s0 := range incStates {
+		for _, s1 := range incStates {
+			for _, s2 := range incStates {
+				prob, err := v.ScorePath([]State{s0, s1, s2})
+				if err != nil {
+					t.Fatal(err)
+				}
+				expected = append(expected, prob)
+			}
+		}
+	}
+	sort.Sort(sort.Reverse(sort.Float64Slice(expected)))
+
+	paths, err := v.EvalPathN(20)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(paths) != len(expected) {
+		t.Fatal(
+			"Expected", len(expected), "paths, but got:", len(paths),
+		)
+	}
+	seen := make(map[[3]State]bool)
+	for i := range paths {
+		if len(paths[i].Path) != 3 {
+			t.Error(
+				"Expected 3 states, but got:", len(paths[i].Path),
+			)
+			continue
+		}
+		if diff := paths[i].Probability - expected[i]; diff > 1e-15 || diff < -1e-15 {
+			t.Error(
+				"Probability of path", i, "has to be", expected[i], "but got", paths[i].Probability,
+			)
+		}
+		key := [3]State{paths[i].Path[0], paths[i].Path[1], paths[i].Path[2]}
+		if seen[key] {
+			t.Error(
+				"Path", i, "is duplicated:", paths[i].Path,
+			)
+		}
+		seen[key] = true
+	}
+
+	best, err := v.EvalPath()
+	if err != nil {
+		t.Fatal(err)
+	}
+	top, err := v.EvalPathN(2)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(top) != 2 {
+		t.Fatal(
+			"Expected 2 paths, but got:", len(top),
+		)
+	}
+	if top[0].Probability != best.Probability {
+		t.Error(
+			"Probability of the best path has to be", best.Probability, "but got", top[0].Probability,
+		)
+	}
+	for i := range best.Path {
+		if top[0].Path[i] != best.Path[i] {
+			t.Error(
+				"State", i, "of the best path has to be", best.Path[i], "but got", top[0].Path[i],
+			)
+		}
+	}
+
+	if _, err := v.EvalPathN(0); err != ErrInvalidPathsNumber {
+		t.Error(
+			"Error has to be ErrInvalidPathsNumber, but got", err,
+		)
+	}
+	if _, err := New().EvalPathN(1); err != ErrNoStates {
+		t.Error(
+			"Error has to be ErrNoStates, but got", err,
+		)
+	}
+}
+
+func TestViterbiEvalPathWithMargin(t *testing.T) {
+	v, incStates, _ := healthModel()
+	paths, err := v.EvalPathN(2)
+	if err != nil {
+		t.Fatal(err)
+	}
+	vpath, margin, err := v.EvalPathWithMargin()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if vpath.Probability != 0.01512 {
+		t.Error(
+			"Probability has to be 0.01512, but got", vpath.Probability,
+		)
+	}
+	expected := math.Log(paths[0].Probability / paths[1].Probability)
+	if math.Abs(margin-expected) > 1e-12 {
+		t.Error(
+			"Margin has to be", expected, ", but got", margin,
+		)
+	}
+	_, logMargin, err := logModel(v).EvalPathWithMarginLogProbabilities()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if math.Abs(logMargin-expected) > 1e-12 {
+		t.Error(
+			"Logarithmic margin has to be", expected, ", but got", logMargin,
+		)
+	}
+
+	// Single valid path has infinite margin
+	single := New()
+	single.AddState(incStates[0])
+	single.AddObservation(CustomObservation{Name: "o", id: 1})
+	single.PutStartProbability(incStates[0], 1)
+	single.PutEmissionProbability(incStates[0], CustomObservation{Name: "o", id: 1}, 1)
+	if _, margin, err := single.EvalPathWithMargin(); err != nil || !math.IsInf(margin, 1) {
+		t.Error(
+			"Margin has to be +Inf, but got", margin, err,
+		)
+	}
+}
+
+func TestViterbiEvalPathWithMarginLongSequence(t *testing.T) {
+	// Classic probabilities of 800 observations underflow without rescaling
+	v := randomModel(5, 800, 3)
+	expected, err := v.EvalPath()
+	if err != nil {
+		t.Fatal(err)
+	}
+	vpath, margin, err := v.EvalPathWithMargin()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if math.Abs(vpath.LogProbability-expected.LogProbability) > 1e-9 {
+		t.Error(
+			"Logarithmic probability has to be", expected.LogProbability, ", but got", vpath.LogProbability,
+		)
+	}
+	_, logMargin, err := v.ToLog().EvalPathWithMarginLogProbabilities()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if math.IsInf(margin, 0) || math.Abs(margin-logMargin) > 1e-6 {
+		t.Error(
+			"Margin has to be", logMargin, ", but got", margin,
+		)
+	}
+}
@@ -0,0 +1,90 @@
+package viterbi
+
+import (
+	"errors"
+	"fmt"
+)
+
+// ErrDimensionMismatch is returned when matrix dimensions do not match number of states or observations
+var ErrDimensionMismatch = errors.New("dimensions mismatch")
+
+// NewFromMatrices builds model from dense matrices:
+// start[i] is start probability of states[i],
+// emission[i][j] is probability of states[i] to emit j-th distinct observation (in order of first appearance in observations),
+// transition[i][j] is probability of transition from states[i] to states[j]
+func NewFromMatrices(states []State, observations []Observation, start []float64, emission [][]float64, transition [][]float64) (*Viterbi, error) {
+	symbols := distinctObservations(observations)
+	if len(start) != len(states) {
+		return nil, fmt.Errorf("%w: %d start probabilities for %d states", ErrDimensionMismatch, len(start), len(states))
+	}
+	if len(emission) != len(states) {
+		return nil, fmt.Errorf("%w: %d emission rows for %d states", ErrDimensionMismatch, len(emission), len(states))
+	}
+	if len(transition) != len(states) {
+		return nil, fmt.Errorf("%w: %d transition rows for %d states", ErrDimensionMismatch, len(transition), len(states))
+	}
+	for i := range states {
+		if len(emission[i]) != len(symbols) {
+			return nil, fmt.Errorf("%w: %d emission columns for %d distinct observations in row %d", ErrDimensionMismatch, len(emission[i]), len(symbols), i)
+		}
+		if len(transition[i]) != len(states) {
+			return nil, fmt.Errorf("%w: %d transition columns for %d states in row %d", ErrDimensionMismatch, len(transition[i]), len(states), i)
+		}
+	}
+
+	v := New()
+	for _, st := range states {
+		v.AddState(st)
+	}
+	for _, obs := range observations {
+		v.AddObservation(obs)
+	}
+	for i, st := range states {
+		v.PutStartProbability(st, start[i])
+		for j, obs := range symbols {
+			v.PutEmissionProbability(st, obs, emission[i][j])
+		}
+		for j, to := range states {
+			v.PutTransitionProbability(st, to, transition[i][j])
+		}
+	}
+	return v, nil
+}
+
+// distinctObservations returns observations without repeats (by ID()) in order of first appearance
+func distinctObservations(observations []Observation) []Observation {
+	seen := make(map[int]struct{}, len(observations))
+	symbols := make([]Observation, 0, len(observations))
+	for _, obs := range observations {
+		if _, ok := seen[obs.ID()]; ok {
+			continue
+		}
+		seen[obs.ID()] = struct{}{}
+		symbols = append(symbols, obs)
+	}
+	return symbols
+}
+
+// ToMatrices exports model parameters as dense matrices indexed by returned orderings:
+// stateOrder is order of added states and obsOrder is distinct observations in order of first appearance.
+// Unset entries are filled with 0. Output could be passed back to NewFromMatrices
+func (v *Viterbi) ToMatrices() (start []float64, emission [][]float64, transition [][]float64, stateOrder []State, obsOrder []Observation) {
+	stateOrder = make([]State, len(v.states))
+	copy(stateOrder, v.states)
+	obsOrder = distinctObservations(v.observations)
+	start = make([]float64, len(stateOrder))
+	emission = make([][]float64, len(stateOrder))
+	transition = make([][]float64, len(stateOrder))
+	for i, st := range stateOrder {
+		start[i] = v.startProbabilities[st.ID()]
+		emission[i] = make([]float64, len(obsOrder))
+		for j, obs := range obsOrder {
+			emission[i][j] = v.emissionProbabilities[EmissionHash{st.ID(), obs.ID()}]
+		}
+		transition[i] = make([]float64, len(stateOrder))
+		for j, to := range stateOrder {
+			transition[i][j] = v.transitionProbabilities[TransitionHash{st.ID(), to.ID()}]
+		}
+	}
+	return start, emission, transition, stateOrder, obsOrder
+}
@@ -0,0 +1,119 @@
+package viterbi
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestNewFromMatrices(t *testing.T) {
+	_, incStates, incomingObservations := healthModel()
+	states := []State{incStates[0], incStates[1]}
+	// Observation 'normal' is repeated, but it's still single emission column
+	observations := []Observation{incomingObservations[0], incomingObservations[1], incomingObservations[2], incomingObservations[0]}
+	start := []float64{0.6, 0.4}
+	emission := [][]float64{
+		{0.5, 0.4, 0.1},
+		{0.1, 0.3, 0.6},
+	}
+	transition := [][]float64{
+		{0.7, 0.3},
+		{0.4, 0.6},
+	}
+	v, err := NewFromMatrices(states, observations, start, emission, transition)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if val, ok := v.GetEmissionProbability(incStates[1], incomingObservations[2]); !ok || val != 0.6 {
+		t.Error(
+			"Emission probability of 'Fever' for 'dizzy' has to be 0.6, but got", val, ok,
+		)
+	}
+	if val, ok := v.GetTransitionProbability(incStates[0], incStates[1]); !ok || val != 0.3 {
+		t.Error(
+			"Transition probability from 'Healty' to 'Fever' has to be 0.3, but got", val, ok,
+		)
+	}
+	vpath, err := v.EvalPath()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(vpath.Path) != 4 {
+		t.Error(
+			"Expected 4 states, but got:", len(vpath.Path),
+		)
+	}
+
+	if _, err := NewFromMatrices(states, observations, start[:1], emission, transition); !errors.Is(err, ErrDimensionMismatch) {
+		t.Error(
+			"Error has to be ErrDimensionMismatch for start probabilities, but got", err,
+		)
+	}
+	if _, err := NewFromMatrices(states, observations, start, [][]float64{{0.5, 0.5}, {0.5, 0.5}}, transition); !errors.Is(err, ErrDimensionMismatch) {
+		t.Error(
+			"Error has to be ErrDimensionMismatch for emission probabilities, but got", err,
+		)
+	}
+	if _, err := NewFromMatrices(states, observations, start, emission, [][]float64{{1}, {1}}); !errors.Is(err, ErrDimensionMismatch) {
+		t.Error(
+			"Error has to be ErrDimensionMismatch for transition probabilities, but got", err,
+		)
+	}
+}
+
+func TestViterbiToMatrices(t *testing.T) {
+	v, incStates, incomingObservations := healthModel()
+	start, emission, transition, stateOrder, obsOrder := v.ToMatrices()
+	if len(stateOrder) != len(incStates) || len(obsOrder) != len(incomingObservations) {
+		t.Fatal(
+			"Expected", len(incStates), "states and", len(incomingObservations), "observations, but got:", len(stateOrder), len(obsOrder),
+		)
+	}
+	if start[0] != 0.6 || start[1] != 0.4 {
+		t.Error(
+			"Start probabilities have to be [0.6 0.4], but got", start,
+		)
+	}
+	if emission[1][2] != 0.6 || transition[1][0] != 0.4 {
+		t.Error(
+			"Unexpected matrices:", emission, transition,
+		)
+	}
+
+	restored, err := NewFromMatrices(stateOrder, obsOrder, start, emission, transition)
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected, err := v.EvalPath()
+	if err != nil {
+		t.Fatal(err)
+	}
+	vpath, err := restored.EvalPath()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if vpath.Probability != expected.Probability {
+		t.Error(
+			"Probability has to be", expected.Probability, "but got", vpath.Probability,
+		)
+	}
+
+	// Unset entries are zeros
+	v.AddState(CustomState{Name: "Unknown", id: 3})
+	start, emission, transition, _, _ = v.ToMatrices()
+	if start[2] != 0 || emission[2][0] != 0 || transition[0][2] != 0 {
+		t.Error(
+			"Unset entries have to be zeros",
+		)
+	}
+}
+
+func TestViterbiToMatricesSameObservationID(t *testing.T) {
+	v, _, _ := healthModel()
+	v.AddObservation(CustomObservation{Name: "normal (again)", id: 1})
+	_, emission, _, _, obsOrder := v.ToMatrices()
+	if len(obsOrder) != 3 || len(emission[0]) != 3 {
+		t.Error(
+			"Observations sharing an ID have to produce a single column, but got", obsOrder,
+		)
+	}
+}
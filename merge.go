@@ -0,0 +1,112 @@
+package viterbi
+
+import (
+	"errors"
+	"fmt"
+)
+
+// ErrMergeConflict is returned by Merge when both models have different probabilities for the same key
+var ErrMergeConflict = errors.New("conflicting probabilities")
+
+// Merge adds states, observations and probabilities of other model to this one, e.g. when emission and transition probabilities
+// are estimated separately. States and observations are united by ID(); start, end, emission, transition (including second-order) and duration probabilities
+// of other model are copied. ErrMergeConflict is returned when both models have different probabilities for the same key:
+// model is left untouched in that case. Use MergeOverwrite to prefer probabilities of other model instead.
+// Settings (defaults, predecessors, constraints and so on) are not merged
+func (v *Viterbi) Merge(other *Viterbi) error {
+	if err := v.mergeConflict(other); err != nil {
+		return err
+	}
+	v.merge(other)
+	return nil
+}
+
+// MergeOverwrite is the same as Merge, but probabilities of other model replace conflicting ones instead of returning ErrMergeConflict
+func (v *Viterbi) MergeOverwrite(other *Viterbi) {
+	v.merge(other)
+}
+
+// mergeConflict returns ErrMergeConflict describing the first found key having different probabilities in both models
+func (v *Viterbi) mergeConflict(other *Viterbi) error {
+	for id, val := range other.startProbabilities {
+		if prev, ok := v.startProbabilities[id]; ok && prev != val {
+			return fmt.Errorf("%w: start probability of state %d is %v, other model has %v", ErrMergeConflict, id, prev, val)
+		}
+	}
+	for id, val := range other.endProbabilities {
+		if prev, ok := v.endProbabilities[id]; ok && prev != val {
+			return fmt.Errorf("%w: end probability of state %d is %v, other model has %v", ErrMergeConflict, id, prev, val)
+		}
+	}
+	for key, val := range other.emissionProbabilities {
+		if prev, ok := v.emissionProbabilities[key]; ok && prev != val {
+			return fmt.Errorf("%w: emission probability of state %d for observation %d is %v, other model has %v", ErrMergeConflict, key.State, key.observation, prev, val)
+		}
+	}
+	for key, val := range other.transitionProbabilities {
+		if prev, ok := v.transitionProbabilities[key]; ok && prev != val {
+			return fmt.Errorf("%w: transition probability from state %d to state %d is %v, other model has %v", ErrMergeConflict, key.From, key.To, prev, val)
+		}
+	}
+	for key, val := range other.transitionProbabilities2 {
+		if prev, ok := v.transitionProbabilities2[key]; ok && prev != val {
+			return fmt.Errorf("%w: transition probability from states %d, %d to state %d is %v, other model has %v", ErrMergeConflict, key.PrevPrev, key.Prev, key.To, prev, val)
+		}
+	}
+	for key, val := range other.durationProbabilities {
+		if prev, ok := v.durationProbabilities[key]; ok && prev != val {
+			return fmt.Errorf("%w: probability of state %d to last %d observations is %v, other model has %v", ErrMergeConflict, key.State, key.Duration, prev, val)
+		}
+	}
+	return nil
+}
+
+// merge adds states, observations and probabilities of other model replacing conflicting probabilities
+func (v *Viterbi) merge(other *Viterbi) {
+	for _, st := range other.states {
+		if _, ok := v.stateWithID(st.ID()); !ok {
+			v.AddState(st)
+		}
+	}
+	for _, obs := range other.observations {
+		if _, ok := v.ObservationByID(obs.ID()); !ok {
+			v.AddObservation(obs)
+		}
+	}
+	if len(other.startProbabilities) > 0 && v.startProbabilities == nil {
+		v.startProbabilities = make(map[int]float64, len(other.startProbabilities))
+	}
+	for id, val := range other.startProbabilities {
+		v.startProbabilities[id] = val
+	}
+	if len(other.endProbabilities) > 0 && v.endProbabilities == nil {
+		v.endProbabilities = make(map[int]float64, len(other.endProbabilities))
+	}
+	for id, val := range other.endProbabilities {
+		v.endProbabilities[id] = val
+	}
+	if len(other.emissionProbabilities) > 0 && v.emissionProbabilities == nil {
+		v.emissionProbabilities = make(map[EmissionHash]float64, len(other.emissionProbabilities))
+	}
+	for key, val := range other.emissionProbabilities {
+		v.emissionProbabilities[key] = val
+	}
+	if len(other.transitionProbabilities) > 0 && v.transitionProbabilities == nil {
+		v.transitionProbabilities = make(map[TransitionHash]float64, len(other.transitionProbabilities))
+	}
+	for key, val := range other.transitionProbabilities {
+		v.transitionProbabilities[key] = val
+	}
+	if len(other.transitionProbabilities2) > 0 && v.transitionProbabilities2 == nil {
+		v.transitionProbabilities2 = make(map[TransitionHash2]float64, len(other.transitionProbabilities2))
+	}
+	for key, val := range other.transitionProbabilities2 {
+		v.transitionProbabilities2[key] = val
+	}
+	if len(other.durationProbabilities) > 0 && v.durationProbabilities == nil {
+		v.durationProbabilities = make(map[DurationHash]float64, len(other.durationProbabilities))
+	}
+	for key, val := range other.durationProbabilities {
+		v.durationProbabilities[key] = val
+	}
+}
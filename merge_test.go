@@ -0,0 +1,114 @@
+package viterbi
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestViterbiMerge(t *testing.T) {
+	_, incStates, incomingObservations := healthModel()
+	healthy, fever := incStates[0], incStates[1]
+	normal, cold, dizzy := incomingObservations[0], incomingObservations[1], incomingObservations[2]
+
+	emissions := New()
+	emissions.AddState(healthy)
+	emissions.AddState(fever)
+	for _, obs := range incomingObservations {
+		emissions.AddObservation(obs)
+	}
+	emissions.PutStartProbability(healthy, 0.6)
+	emissions.PutStartProbability(fever, 0.4)
+	emissions.PutEmissionProbability(healthy, normal, 0.5)
+	emissions.PutEmissionProbability(healthy, cold, 0.4)
+	emissions.PutEmissionProbability(healthy, dizzy, 0.1)
+	emissions.PutEmissionProbability(fever, normal, 0.1)
+	emissions.PutEmissionProbability(fever, cold, 0.3)
+	emissions.PutEmissionProbability(fever, dizzy, 0.6)
+
+	transitions := New()
+	transitions.AddState(fever)
+	transitions.AddState(healthy)
+	transitions.PutStartProbability(healthy, 0.6)
+	transitions.PutTransitionProbability(healthy, healthy, 0.7)
+	transitions.PutTransitionProbability(healthy, fever, 0.3)
+	transitions.PutTransitionProbability(fever, healthy, 0.4)
+	transitions.PutTransitionProbability(fever, fever, 0.6)
+
+	if err := emissions.Merge(transitions); err != nil {
+		t.Fatal(err)
+	}
+	if len(emissions.states) != 2 || len(emissions.observations) != 3 {
+		t.Error(
+			"States and observations have to be united by ID, but got", emissions.states, emissions.observations,
+		)
+	}
+	vpath, err := emissions.EvalPath()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if vpath.String() != "0.01512: [1 1 2]" {
+		t.Error(
+			"Path has to be '0.01512: [1 1 2]', but got", vpath,
+		)
+	}
+
+	conflicting := New()
+	conflicting.AddState(healthy)
+	conflicting.PutTransitionProbability(healthy, fever, 0.5)
+	conflicting.PutTransitionProbability(fever, CustomState{Name: "Dizzy", id: 3}, 0.5)
+	if err := emissions.Merge(conflicting); !errors.Is(err, ErrMergeConflict) {
+		t.Fatal(
+			"Error has to be ErrMergeConflict, but got", err,
+		)
+	}
+	if emissions.transitionProbabilities[TransitionHash{healthy.ID(), fever.ID()}] != 0.3 || len(emissions.transitionProbabilities) != 4 {
+		t.Error(
+			"Model has to be left untouched on conflict, but got", emissions.transitionProbabilities,
+		)
+	}
+
+	emissions.MergeOverwrite(conflicting)
+	if emissions.transitionProbabilities[TransitionHash{healthy.ID(), fever.ID()}] != 0.5 || len(emissions.transitionProbabilities) != 5 {
+		t.Error(
+			"Probabilities of other model have to be preferred, but got", emissions.transitionProbabilities,
+		)
+	}
+}
+
+func TestViterbiMergeDurations(t *testing.T) {
+	v, incStates, _ := healthModel()
+	v.SetDurationProbability(incStates[0], 1, 0.5)
+
+	durations := New()
+	durations.AddState(incStates[0])
+	durations.SetDurationProbability(incStates[0], 2, 0.5)
+	if err := v.Merge(durations); err != nil {
+		t.Fatal(err)
+	}
+	if len(v.durationProbabilities) != 2 || v.durationProbabilities[DurationHash{incStates[0].ID(), 2}] != 0.5 {
+		t.Error(
+			"Duration probabilities have to be merged, but got", v.durationProbabilities,
+		)
+	}
+
+	conflicting := New()
+	conflicting.AddState(incStates[0])
+	conflicting.SetDurationProbability(incStates[0], 1, 0.3)
+	if err := v.Merge(conflicting); !errors.Is(err, ErrMergeConflict) {
+		t.Fatal(
+			"Error has to be ErrMergeConflict, but got", err,
+		)
+	}
+	if v.durationProbabilities[DurationHash{incStates[0].ID(), 1}] != 0.5 {
+		t.Error(
+			"Model has to be left untouched on conflict, but got", v.durationProbabilities,
+		)
+	}
+
+	v.MergeOverwrite(conflicting)
+	if v.durationProbabilities[DurationHash{incStates[0].ID(), 1}] != 0.3 {
+		t.Error(
+			"Probabilities of other model have to be preferred, but got", v.durationProbabilities,
+		)
+	}
+}
@@ -0,0 +1,70 @@
+package viterbi
+
+import (
+	"errors"
+	"fmt"
+	"math"
+)
+
+// ErrProbabilityTooLow is returned when the best partial path falls below minimum probability (evaluators wrap it into *ProbabilityTooLowError)
+var ErrProbabilityTooLow = errors.New("probability of the best partial path is below minimum")
+
+// ProbabilityTooLowError describes observation at which evaluation has been stopped (see SetMinProbability). It matches ErrProbabilityTooLow via errors.Is
+type ProbabilityTooLowError struct {
+	// ObservationIndex is position of the observation in the sequence
+	ObservationIndex int
+	// Probability is probability of the best partial path ending at the observation (logarithmic in logarithmic mode)
+	Probability float64
+}
+
+func (e *ProbabilityTooLowError) Error() string {
+	return fmt.Sprintf("%s at observation %d: %v", ErrProbabilityTooLow, e.ObservationIndex, e.Probability)
+}
+
+// Unwrap returns ErrProbabilityTooLow, so errors.Is(err, ErrProbabilityTooLow) holds
+func (e *ProbabilityTooLowError) Unwrap() error {
+	return ErrProbabilityTooLow
+}
+
+// checkMinProbability returns *ProbabilityTooLowError when the best value of trellis column t is below minimum probability.
+// logScale is logarithm of scaling factor of classic probabilities accumulated up to the column (inclusive)
+func (v *Viterbi) checkMinProbability(column map[State]ViterbiVal, t int, logSpace bool, logScale float64) error {
+	if v.minProbability == nil {
+		return nil
+	}
+	best := math.Inf(-1)
+	for _, value := range column {
+		if prob := logProbability(logSpace, value.prob) + logScale; prob > best {
+			best = prob
+		}
+	}
+	return v.checkMinLogProbability(best, t, logSpace)
+}
+
+// checkDenseMinProbability is the same as checkMinProbability, but for dense trellis column
+func (v *Viterbi) checkDenseMinProbability(column denseColumn, t int, logSpace bool, logScale float64) error {
+	if v.minProbability == nil {
+		return nil
+	}
+	best := math.Inf(-1)
+	for i, value := range column.values {
+		if !column.set[i] {
+			continue
+		}
+		if prob := logProbability(logSpace, value.prob) + logScale; prob > best {
+			best = prob
+		}
+	}
+	return v.checkMinLogProbability(best, t, logSpace)
+}
+
+// checkMinLogProbability returns *ProbabilityTooLowError when logarithm of the best probability at observation t is below minimum probability
+func (v *Viterbi) checkMinLogProbability(best float64, t int, logSpace bool) error {
+	if best >= logProbability(logSpace, *v.minProbability) {
+		return nil
+	}
+	if !logSpace {
+		best = math.Exp(best)
+	}
+	return &ProbabilityTooLowError{ObservationIndex: t, Probability: best}
+}
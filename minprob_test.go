@@ -0,0 +1,45 @@
+package viterbi
+
+import (
+	"errors"
+	"math"
+	"testing"
+)
+
+func TestViterbiSetMinProbability(t *testing.T) {
+	v, _, _ := healthModel()
+	// Best partial paths: 0.3, 0.084, 0.01512
+	v.SetMinProbability(0.01)
+	if _, err := v.EvalPath(); err != nil {
+		t.Fatal(err)
+	}
+
+	v.SetMinProbability(0.02)
+	_, err := v.EvalPath()
+	var lowErr *ProbabilityTooLowError
+	if !errors.Is(err, ErrProbabilityTooLow) || !errors.As(err, &lowErr) {
+		t.Fatal(
+			"Error has to be *ProbabilityTooLowError, but got", err,
+		)
+	}
+	if lowErr.ObservationIndex != 2 || math.Abs(lowErr.Probability-0.01512) > 1e-12 {
+		t.Error(
+			"Evaluation has to stop at observation 2 with probability 0.01512, but got", lowErr.ObservationIndex, lowErr.Probability,
+		)
+	}
+	_, err = v.EvalPathCompact()
+	if !errors.As(err, &lowErr) || lowErr.ObservationIndex != 2 {
+		t.Error(
+			"Compact evaluation has to stop at observation 2, but got", err,
+		)
+	}
+
+	logV := logModel(v)
+	logV.SetMinProbability(math.Log(0.1))
+	_, err = logV.EvalPathLogProbabilities()
+	if !errors.As(err, &lowErr) || lowErr.ObservationIndex != 1 || math.Abs(lowErr.Probability-math.Log(0.084)) > 1e-12 {
+		t.Error(
+			"Evaluation has to stop at observation 1 with log probability log(0.084), but got", err,
+		)
+	}
+}
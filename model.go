@@ -0,0 +1,18 @@
+package viterbi
+
+// Decode evaluates the most probable path of states for given observations sequence (see EvalPath).
+// Model is not changed, so it's safe to decode different sequences from several goroutines as long as nobody changes the model at the same time.
+// Every probability has to be in [0;1].
+func (m *Model) Decode(obs []Observation) (ViterbiPath, error) {
+	return m.decoder(obs).evalPath(false)
+}
+
+// DecodeLogProbabilities is the same as Decode, but when every probability is logarithmic
+func (m *Model) DecodeLogProbabilities(obs []Observation) (ViterbiPath, error) {
+	return m.decoder(obs).evalPath(true)
+}
+
+// decoder binds model with observations sequence
+func (m *Model) decoder(obs []Observation) *Viterbi {
+	return &Viterbi{Model: *m, observations: obs}
+}
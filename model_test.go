@@ -0,0 +1,64 @@
+package viterbi
+
+import (
+	"testing"
+)
+
+func TestModelDecode(t *testing.T) {
+	v, incStates, incomingObservations := healthModel()
+	correctPath, err := v.EvalPath()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	m := NewModel()
+	for i := range incStates {
+		m.AddState(incStates[i])
+	}
+	for _, s := range incStates {
+		prob, _ := v.GetStartProbability(s)
+		m.PutStartProbability(s, prob)
+		for _, obs := range incomingObservations {
+			prob, _ := v.GetEmissionProbability(s, obs)
+			m.PutEmissionProbability(s, obs, prob)
+		}
+		for _, to := range incStates {
+			prob, _ := v.GetTransitionProbability(s, to)
+			m.PutTransitionProbability(s, to, prob)
+		}
+	}
+
+	obs := []Observation{incomingObservations[0], incomingObservations[1], incomingObservations[2]}
+	vpath, err := m.Decode(obs)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if vpath.Probability != correctPath.Probability {
+		t.Error(
+			"Probability has to be", correctPath.Probability, ", but got", vpath.Probability,
+		)
+	}
+	for i := range correctPath.Path {
+		if vpath.Path[i].ID() != correctPath.Path[i].ID() {
+			t.Error(
+				"State on position", i, "has to be", correctPath.Path[i], ", but got", vpath.Path[i],
+			)
+		}
+	}
+
+	logPath, err := v.ToLog().Model.DecodeLogProbabilities(obs)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(logPath.Path) != len(obs) {
+		t.Error(
+			"Path length has to be", len(obs), ", but got", len(logPath.Path),
+		)
+	}
+
+	if _, err := NewModel().Decode(obs); err != ErrNoStates {
+		t.Error(
+			"Error has to be ErrNoStates, but got", err,
+		)
+	}
+}
@@ -0,0 +1,33 @@
+package viterbi
+
+import (
+	"fmt"
+	"reflect"
+)
+
+// isNil reports whether value is nil interface or interface holding nil pointer (map, slice and so on)
+func isNil(val interface{}) bool {
+	if val == nil {
+		return true
+	}
+	rv := reflect.ValueOf(val)
+	switch rv.Kind() {
+	case reflect.Ptr, reflect.Map, reflect.Slice, reflect.Func, reflect.Chan, reflect.Interface:
+		return rv.IsNil()
+	}
+	return false
+}
+
+// mustState panics with ErrNilState when nil state is passed to method
+func mustState(s State, method string) {
+	if isNil(s) {
+		panic(fmt.Errorf("%w: passed to %s", ErrNilState, method))
+	}
+}
+
+// mustObservation panics with ErrNilObservation when nil observation is passed to method
+func mustObservation(obs Observation, method string) {
+	if isNil(obs) {
+		panic(fmt.Errorf("%w: passed to %s", ErrNilObservation, method))
+	}
+}
@@ -0,0 +1,90 @@
+package viterbi
+
+import (
+	"errors"
+	"testing"
+)
+
+// pointerState is state with pointer receiver, so typed nil does not panic by itself
+type pointerState struct {
+	id int
+}
+
+func (s *pointerState) ID() int {
+	if s == nil {
+		return 0
+	}
+	return s.id
+}
+
+func TestViterbiNilArguments(t *testing.T) {
+	expectPanic := func(name string, target error, fn func()) {
+		defer func() {
+			rec := recover()
+			err, ok := rec.(error)
+			if !ok || !errors.Is(err, target) {
+				t.Error(
+					name, "has to panic with", target, ", but got", rec,
+				)
+			}
+		}()
+		fn()
+	}
+	var (
+		st       = CustomState{Name: "s", id: 1}
+		obs      = CustomObservation{Name: "o", id: 1}
+		typedNil *pointerState
+	)
+	v := New()
+	expectPanic("AddState", ErrNilState, func() { v.AddState(nil) })
+	expectPanic("AddState with typed nil", ErrNilState, func() { v.AddState(typedNil) })
+	expectPanic("AddObservation", ErrNilObservation, func() { v.AddObservation(nil) })
+	expectPanic("PutStartProbability", ErrNilState, func() { v.PutStartProbability(nil, 1) })
+	expectPanic("PutEmissionProbability", ErrNilObservation, func() { v.PutEmissionProbability(st, nil, 1) })
+	expectPanic("PutTransitionProbability", ErrNilState, func() { v.PutTransitionProbability(st, typedNil, 1) })
+
+	if err := v.AddStateChecked(typedNil); err != ErrNilState {
+		t.Error(
+			"Error has to be ErrNilState, but got", err,
+		)
+	}
+	if len(v.states) != 0 || len(v.startProbabilities) != 0 || len(v.emissionProbabilities) != 0 || len(v.transitionProbabilities) != 0 {
+		t.Error(
+			"Nil arguments have not to be stored",
+		)
+	}
+
+	v.AddState(&pointerState{id: 2})
+	v.PutEmissionProbability(st, obs, 1)
+}
+
+func TestViterbiFromJSONTypedNil(t *testing.T) {
+	v, incStates, incomingObservations := healthModel()
+	data, err := v.ToJSON()
+	if err != nil {
+		t.Fatal(err)
+	}
+	var typedNil *pointerState
+	stateByID := func(id int) State {
+		return incStates[id-1]
+	}
+	obsByID := func(id int) Observation {
+		return incomingObservations[id-1]
+	}
+
+	if _, err := FromJSON(data, func(id int) State { return typedNil }, obsByID); !errors.Is(err, ErrUnknownState) {
+		t.Error(
+			"Error has to be ErrUnknownState for typed nil state, but got", err,
+		)
+	}
+	if _, err := FromJSON(data, stateByID, func(id int) Observation { return typedNil }); !errors.Is(err, ErrUnknownObservation) {
+		t.Error(
+			"Error has to be ErrUnknownObservation for typed nil observation, but got", err,
+		)
+	}
+	if _, err := FromJSON(data, stateByID, obsByID); err != nil {
+		t.Error(
+			"Model has to be restored, but got", err,
+		)
+	}
+}
@@ -0,0 +1,65 @@
+package viterbi
+
+import (
+	"errors"
+	"fmt"
+	"math"
+)
+
+// ErrZeroSum is returned when group of probabilities can't be normalized since it sums to zero
+var ErrZeroSum = errors.New("probabilities sum to zero")
+
+// Normalize rescales stored values in place so that they become classic probabilities:
+// start probabilities sum to 1, emission probabilities of every state sum to 1 over observations and
+// transition probabilities from every state sum to 1 over destinations (with respect to orientation, see SetTransitionOrientation).
+// It is useful when model has been built from raw counts.
+// Model stays untouched if some value is negative or some group sums to zero
+func (v *Viterbi) Normalize() error {
+	startSum := 0.0
+	for st, val := range v.startProbabilities {
+		if val < 0 || math.IsNaN(val) {
+			return fmt.Errorf("%w: start probability %v of state with ID %d", ErrInvalidProbability, val, st)
+		}
+		startSum += val
+	}
+	if len(v.startProbabilities) != 0 && startSum == 0 {
+		return fmt.Errorf("%w: start probabilities", ErrZeroSum)
+	}
+
+	emissionSums := make(map[int]float64)
+	for key, val := range v.emissionProbabilities {
+		if val < 0 || math.IsNaN(val) {
+			return fmt.Errorf("%w: emission probability %v of state with ID %d for observation with ID %d", ErrInvalidProbability, val, key.State, key.observation)
+		}
+		emissionSums[key.State] += val
+	}
+	for st, sum := range emissionSums {
+		if sum == 0 {
+			return fmt.Errorf("%w: emission probabilities of state with ID %d", ErrZeroSum, st)
+		}
+	}
+
+	transitionSums := make(map[int]float64)
+	for key, val := range v.transitionProbabilities {
+		if val < 0 || math.IsNaN(val) {
+			return fmt.Errorf("%w: transition probability %v from state with ID %d to state with ID %d", ErrInvalidProbability, val, key.From, key.To)
+		}
+		transitionSums[v.transitionSource(key)] += val
+	}
+	for st, sum := range transitionSums {
+		if sum == 0 {
+			return fmt.Errorf("%w: transition probabilities from state with ID %d", ErrZeroSum, st)
+		}
+	}
+
+	for st := range v.startProbabilities {
+		v.startProbabilities[st] /= startSum
+	}
+	for key := range v.emissionProbabilities {
+		v.emissionProbabilities[key] /= emissionSums[key.State]
+	}
+	for key := range v.transitionProbabilities {
+		v.transitionProbabilities[key] /= transitionSums[v.transitionSource(key)]
+	}
+	return nil
+}
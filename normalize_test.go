@@ -0,0 +1,71 @@
+package viterbi
+
+import (
+	"errors"
+	"math"
+	"testing"
+)
+
+func TestViterbiNormalize(t *testing.T) {
+	var (
+		incStates = []CustomState{
+			CustomState{Name: "Healty", id: 1},
+			CustomState{Name: "Fever", id: 2},
+		}
+		incomingObservations = []CustomObservation{
+			CustomObservation{Name: "normal", id: 1},
+			CustomObservation{Name: "cold", id: 2},
+			CustomObservation{Name: "dizzy", id: 3},
+		}
+	)
+	v := New()
+	for i := range incStates {
+		v.AddState(incStates[i])
+	}
+	for i := range incomingObservations {
+		v.AddObservation(incomingObservations[i])
+	}
+
+	// Raw counts of the classic example
+	v.PutStartProbability(incStates[0], 6)
+	v.PutStartProbability(incStates[1], 4)
+
+	v.PutEmissionProbability(incStates[0], incomingObservations[0], 5)
+	v.PutEmissionProbability(incStates[0], incomingObservations[1], 4)
+	v.PutEmissionProbability(incStates[0], incomingObservations[2], 1)
+	v.PutEmissionProbability(incStates[1], incomingObservations[0], 1)
+	v.PutEmissionProbability(incStates[1], incomingObservations[1], 3)
+	v.PutEmissionProbability(incStates[1], incomingObservations[2], 6)
+
+	v.PutTransitionProbability(incStates[0], incStates[0], 7)
+	v.PutTransitionProbability(incStates[0], incStates[1], 3)
+	v.PutTransitionProbability(incStates[1], incStates[0], 4)
+	v.PutTransitionProbability(incStates[1], incStates[1], 6)
+
+	if _, err := v.EvalPath(); !errors.Is(err, ErrInvalidProbability) {
+		t.Error(
+			"Error has to be ErrInvalidProbability before normalization, but got", err,
+		)
+	}
+	if err := v.Normalize(); err != nil {
+		t.Fatal(err)
+	}
+	vpath, err := v.EvalPath()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if math.Abs(vpath.Probability-0.01512) > 1e-12 {
+		t.Error(
+			"Probability has to be 0.01512, but got", vpath.Probability,
+		)
+	}
+
+	zero := New()
+	zero.AddState(incStates[0])
+	zero.PutTransitionProbability(incStates[0], incStates[1], 0)
+	if err := zero.Normalize(); !errors.Is(err, ErrZeroSum) {
+		t.Error(
+			"Error has to be ErrZeroSum, but got", err,
+		)
+	}
+}
@@ -0,0 +1,233 @@
+package viterbi
+
+// SetDefaultTransitionProbability sets probability which is used for every pair of states without transition probability.
+// Pairs with stored probability (explicit zero included) and forbidden ones (see ForbidTransition) are not filled.
+// By default such transitions are impossible
+func (m *Model) SetDefaultTransitionProbability(val float64) {
+	m.defaultTransition = &val
+}
+
+// ForbidTransition marks transition between states as impossible regardless of stored, default and computed (see SetTransitionFunc) probabilities,
+// so graph topology could be asserted without knowing whether probabilities are classic or logarithmic.
+// Forbidden transition is not missing: it's impossible the same way as transition with explicit zero probability
+func (m *Model) ForbidTransition(from, to State) {
+	mustState(from, "ForbidTransition")
+	mustState(to, "ForbidTransition")
+	if m.forbiddenTransitions == nil {
+		m.forbiddenTransitions = make(map[TransitionHash]struct{})
+	}
+	m.forbiddenTransitions[TransitionHash{from.ID(), to.ID()}] = struct{}{}
+}
+
+// SetDefaultEmissionProbability sets probability which is used for every pair of state and observation without emission probability.
+// By default such emissions are impossible
+func (m *Model) SetDefaultEmissionProbability(val float64) {
+	m.defaultEmission = &val
+}
+
+// SetEmissionWeight sets relative influence of emissions: every emission probability is raised to the power of w
+// (logarithmic one is multiplied by w), e.g. to tune trust in GPS accuracy against routing plausibility in map matching.
+// Weight of 1 keeps probabilities as is (default)
+func (m *Model) SetEmissionWeight(w float64) {
+	m.emissionWeight = &w
+}
+
+// SetTransitionWeight is the same as SetEmissionWeight, but for transition probabilities
+func (m *Model) SetTransitionWeight(w float64) {
+	m.transitionWeight = &w
+}
+
+// SetBeamWidth enables beam search: after evaluation of every trellis column only k most probable states are kept
+// for the next observation, the rest are pruned. It trades exactness for speed.
+// Beam width of 0 disables pruning (default)
+func (m *Model) SetBeamWidth(k int) {
+	m.beamWidth = k
+}
+
+// SetPruneRatio enables relative pruning: after evaluation of every trellis column states with probability below r·max(column)
+// are pruned, so number of kept states adapts to how peaked the column is. It could be combined with beam width (see SetBeamWidth).
+// Ratio of 0 disables pruning (default)
+func (m *Model) SetPruneRatio(r float64) {
+	m.pruneRatio = r
+}
+
+// SetAmbiguityEpsilon enables marking of ambiguous observations in ViterbiPath.Ambiguous: observation is ambiguous when
+// runner-up state would have lost to state of the path by no more than eps. For the last observation states are compared by final probability,
+// for the rest by probability of reaching next state of the path. Epsilon is relative difference for classic probabilities
+// and absolute difference for logarithmic ones. By default ambiguity is not evaluated
+func (m *Model) SetAmbiguityEpsilon(eps float64) {
+	m.ambiguityEpsilon = &eps
+}
+
+// SetMinProbability enables early termination: when probability of the best partial path ending at some observation
+// falls below p, evaluation stops with *ProbabilityTooLowError (matching ErrProbabilityTooLow) instead of decoding the rest of the sequence.
+// Threshold is compared in the same scale as probabilities of the model: classic for EvalPath, logarithmic for EvalPathLogProbabilities.
+// By default there is no minimum
+func (m *Model) SetMinProbability(p float64) {
+	m.minProbability = &p
+}
+
+// SetEmissionsAreDensities allows emissions to be unnormalized likelihoods (e.g. values of Gaussian density) instead of probabilities:
+// classic emissions above 1 are not rejected with ErrInvalidProbability (negative ones still are), as well as positive logarithmic ones by ValidateModelLogProbabilities.
+// Every state is scored by the same observation at given timestep, so the best path is not affected by the scale of densities,
+// but probability of the path becomes likelihood. Emissions are probabilities by default
+func (m *Model) SetEmissionsAreDensities(densities bool) {
+	m.emissionDensities = densities
+}
+
+// TransitionOrientation defines how stored transition probabilities are interpreted by evaluators
+type TransitionOrientation int
+
+const (
+	// TransitionFromTo means probability put via PutTransitionProbability(a, b, val) is P(b|a), i.e. transition from a to b (default)
+	TransitionFromTo TransitionOrientation = iota
+	// TransitionToFrom means probability put via PutTransitionProbability(a, b, val) is P(a|b), i.e. transition from b to a
+	// (e.g. for column-stochastic matrix imported as is)
+	TransitionToFrom
+)
+
+// SetTransitionOrientation sets how evaluators look stored transition probabilities up. Probabilities are stored as put,
+// and GetTransitionProbability and export functions treat them as (from, to) pairs regardless of orientation.
+// Normalize, Train, Fit, Finalize and Sample honour orientation (e.g. Normalize makes every column sum to 1 for TransitionToFrom).
+// Transition functions, predecessors and forbidden transitions are not affected. Default orientation is TransitionFromTo
+func (m *Model) SetTransitionOrientation(orientation TransitionOrientation) {
+	m.transitionOrientation = orientation
+}
+
+// transitionKey returns key of stored probability of transition from one state to another with respect to orientation
+func (m *Model) transitionKey(from, to State) TransitionHash {
+	if m.transitionOrientation == TransitionToFrom {
+		return TransitionHash{to.ID(), from.ID()}
+	}
+	return TransitionHash{from.ID(), to.ID()}
+}
+
+// transitionSource returns ID of source state of stored transition probability with respect to orientation
+func (m *Model) transitionSource(key TransitionHash) int {
+	if m.transitionOrientation == TransitionToFrom {
+		return key.To
+	}
+	return key.From
+}
+
+// SetTieBreaker sets rule resolving equal probabilities wherever evaluators choose between states (trellis cells, final state, beam pruning and so on):
+// less(a, b) reports whether state a has to be chosen over state b. It has to be strict order (e.g. by domain cost or order of adding),
+// otherwise result depends on evaluation order. Passing nil restores default rule: the state with the lowest ID() wins
+func (m *Model) SetTieBreaker(less func(a, b State) bool) {
+	m.tieBreaker = less
+}
+
+// SetProbabilityTolerance allows classic probabilities to be out of [0;1] range by eps (e.g. 1.0000000002 after floating-point arithmetic):
+// such values are clamped into the range instead of being rejected with ErrInvalidProbability.
+// Tolerance of 0 rejects every value out of range (default)
+func (m *Model) SetProbabilityTolerance(eps float64) {
+	m.tolerance = eps
+}
+
+// SetStrictTransitions turns missing transition probability between state of previous observation and state of current one into ErrMissingTransition
+// naming both states, instead of treating such transition as impossible. Default and computed (see SetTransitionFunc) transitions are not missing;
+// transitions excluded by SetPredecessors are not evaluated at all. Strict mode is disabled by default
+func (m *Model) SetStrictTransitions(strict bool) {
+	m.strictTransitions = strict
+}
+
+// SetEmissionFunc sets function evaluating emission probability for pairs of state and observation without stored one
+// (e.g. density of continuous observation). Probabilities put via PutEmissionProbability take precedence over the function.
+// Passing nil removes the function
+func (m *Model) SetEmissionFunc(fn func(s State, obs Observation) float64) {
+	m.emissionFunc = fn
+}
+
+// SetTransitionFunc sets function evaluating transition probability for pairs of states without stored one
+// (e.g. from routing distance between road segments). Second return value of the function reports whether transition is possible at all:
+// when it's false the transition is skipped and default transition probability is not used.
+// Probabilities put via PutTransitionProbability take precedence over the function. Passing nil removes the function
+func (m *Model) SetTransitionFunc(fn func(from, to State) (float64, bool)) {
+	m.transitionFunc = fn
+}
+
+// SetPredecessors restricts states transition to s is evaluated from to preds, so sparse models do not scan every state for every cell.
+// Transitions from other states are ignored even when probability for them has been put. Predecessors are matched by ID(),
+// so they could be different values than added states.
+// States without registered predecessors are reached from every state (default). Passing nil preds removes restriction
+func (m *Model) SetPredecessors(s State, preds []State) {
+	if preds == nil {
+		delete(m.predecessors, s.ID())
+		return
+	}
+	if m.predecessors == nil {
+		m.predecessors = make(map[int][]State)
+	}
+	resolved := make([]State, len(preds))
+	for i, st := range preds {
+		mustState(st, "SetPredecessors")
+		resolved[i] = st
+		if added, ok := m.stateWithID(st.ID()); ok {
+			resolved[i] = added
+		}
+	}
+	m.predecessors[s.ID()] = resolved
+}
+
+// predecessorsOf returns states transition to s has to be evaluated from
+func (m *Model) predecessorsOf(s State) []State {
+	if preds, ok := m.predecessors[s.ID()]; ok {
+		return preds
+	}
+	return m.states
+}
+
+// isPredecessor reports whether transition from state from to state to is evaluated according to predecessors (see SetPredecessors)
+func (m *Model) isPredecessor(from, to State) bool {
+	preds, ok := m.predecessors[to.ID()]
+	if !ok {
+		return true
+	}
+	for _, st := range preds {
+		if st.ID() == from.ID() {
+			return true
+		}
+	}
+	return false
+}
+
+// SetExpectedActiveStates sets expected number of reachable states per observation, so trellis column is allocated
+// with that capacity up front instead of growing. Value of 0 disables preallocation (default)
+func (m *Model) SetExpectedActiveStates(n int) {
+	m.expectedActive = n
+}
+
+// newColumn allocates trellis column according to expected number of active states
+func (m *Model) newColumn() map[State]ViterbiVal {
+	if m.expectedActive > 0 {
+		return make(map[State]ViterbiVal, m.expectedActive)
+	}
+	return make(map[State]ViterbiVal)
+}
+
+// SetUnknownEmission sets probability which is used for every state when observation has no emission probability for any state
+// (e.g. word never seen in training data), so such observation does not break the path.
+// Observations known to at least one state are not affected (see SetDefaultEmissionProbability for that).
+// Check of unknown observation scans every state once per observation
+func (m *Model) SetUnknownEmission(val float64) {
+	m.unknownEmission = &val
+}
+
+// unknownAt reports whether unknown emission probability applies to observation with index t (see SetUnknownEmission)
+func (v *Viterbi) unknownAt(t int) bool {
+	return v.unknownEmission != nil && v.unknownObservation(t)
+}
+
+// unknownObservation reports whether observation with index t has no stored emission probability (time-indexed one included) for any state
+func (v *Viterbi) unknownObservation(t int) bool {
+	id := v.observations[t].ID()
+	for _, st := range v.states {
+		if _, ok := v.emissionProbabilities[EmissionHash{st.ID(), id}]; ok {
+			return false
+		}
+		if _, ok := v.timedEmissions[TimedEmissionHash{st.ID(), t}]; ok {
+			return false
+		}
+	}
+	return true
+}
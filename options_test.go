@@ -0,0 +1,701 @@
+package viterbi
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"math/rand"
+	"testing"
+)
+
+func TestViterbiDefaultProbabilities(t *testing.T) {
+	var (
+		incStates = []CustomState{
+			CustomState{Name: "a", id: 1},
+			CustomState{Name: "b", id: 2},
+		}
+		observations = []CustomObservation{
+			CustomObservation{Name: "o1", id: 1},
+			CustomObservation{Name: "o2", id: 2},
+		}
+	)
+	v := New()
+	for i := range incStates {
+		v.AddState(incStates[i])
+	}
+	for i := range observations {
+		v.AddObservation(observations[i])
+	}
+	v.PutStartProbability(incStates[0], 1.0)
+	v.PutEmissionProbability(incStates[0], observations[0], 1.0)
+	v.PutEmissionProbability(incStates[1], observations[1], 1.0)
+
+	// There is no transition from 'a' to 'b'
+	if _, err := v.EvalPath(); !errors.Is(err, ErrPathBroken) {
+		t.Error(
+			"Error has to be ErrPathBroken, but got", err,
+		)
+	}
+
+	v.SetDefaultTransitionProbability(0.01)
+	vpath, err := v.EvalPath()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if vpath.Path[0] != incStates[0] || vpath.Path[1] != incStates[1] {
+		t.Error(
+			"Path has to be [a b], but got", vpath.Path,
+		)
+	}
+	if math.Abs(vpath.Probability-0.01) > 1e-12 {
+		t.Error(
+			"Probability has to be 0.01, but got", vpath.Probability,
+		)
+	}
+
+	// Default emission makes 'a' possible for the second observation too
+	v.PutTransitionProbability(incStates[0], incStates[0], 0.5)
+	v.SetDefaultEmissionProbability(0.1)
+	vpath, err = v.EvalPath()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if math.Abs(vpath.Probability-0.05) > 1e-12 || vpath.Path[1] != incStates[0] {
+		t.Error(
+			"Path has to be [a a] with probability 0.05, but got", vpath,
+		)
+	}
+}
+
+func TestViterbiProbabilityTolerance(t *testing.T) {
+	v, incStates, incomingObservations := healthModel()
+	delete(v.emissionProbabilities, EmissionHash{incStates[0].ID(), incomingObservations[0].ID()})
+	v.PutEmissionProbability(incStates[0], incomingObservations[0], 1.0000000002)
+	if _, err := v.EvalPath(); !errors.Is(err, ErrInvalidProbability) {
+		t.Error(
+			"Error has to be ErrInvalidProbability without tolerance, but got", err,
+		)
+	}
+
+	v.SetProbabilityTolerance(1e-9)
+	vpath, err := v.EvalPath()
+	if err != nil {
+		t.Fatal(err)
+	}
+	// Clamped emission is exactly 1
+	_, trellis, err := v.EvalPathWithTrellis()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if trellis[0][0].Probability != 0.6 {
+		t.Error(
+			"Probability of 'Healty' at first observation has to be 0.6 * 1, but got", trellis[0][0].Probability,
+		)
+	}
+	if len(vpath.Path) != len(incomingObservations) {
+		t.Error(
+			"Path length has to be", len(incomingObservations), ", but got", len(vpath.Path),
+		)
+	}
+
+	delete(v.emissionProbabilities, EmissionHash{incStates[0].ID(), incomingObservations[0].ID()})
+	v.PutEmissionProbability(incStates[0], incomingObservations[0], 1.001)
+	if _, err := v.EvalPath(); !errors.Is(err, ErrInvalidProbability) {
+		t.Error(
+			"Error has to be ErrInvalidProbability out of tolerance, but got", err,
+		)
+	}
+}
+
+func TestViterbiEmissionFunc(t *testing.T) {
+	v, incStates, incomingObservations := healthModel()
+	correctPath, err := v.EvalPath()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// The same emissions evaluated by function
+	emissions := make(map[EmissionHash]float64)
+	for key, val := range v.emissionProbabilities {
+		emissions[key] = val
+	}
+	for key := range v.emissionProbabilities {
+		delete(v.emissionProbabilities, key)
+	}
+	calls := 0
+	v.SetEmissionFunc(func(s State, obs Observation) float64 {
+		calls++
+		return emissions[EmissionHash{s.ID(), obs.ID()}]
+	})
+	vpath, err := v.EvalPath()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if calls == 0 || vpath.Probability != correctPath.Probability {
+		t.Error(
+			"Probability has to be", correctPath.Probability, ", but got", vpath.Probability,
+		)
+	}
+
+	logPath, err := v.ToLog().EvalPathLogProbabilities()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if math.Abs(math.Exp(logPath.Probability)-correctPath.Probability) > 1e-12 {
+		t.Error(
+			"Probability has to be", correctPath.Probability, ", but got", math.Exp(logPath.Probability),
+		)
+	}
+
+	// Stored emission takes precedence over the function
+	v.PutEmissionProbability(incStates[1], incomingObservations[2], 0)
+	vpath, err = v.EvalPath()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if vpath.Path[2].ID() != incStates[0].ID() {
+		t.Error(
+			"State on position 2 has to be", incStates[0], ", but got", vpath.Path[2],
+		)
+	}
+}
+
+func TestViterbiTransitionFunc(t *testing.T) {
+	v, incStates, _ := healthModel()
+	correctPath, err := v.EvalPath()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	transitions := make(map[TransitionHash]float64)
+	for key, val := range v.transitionProbabilities {
+		transitions[key] = val
+	}
+	for key := range v.transitionProbabilities {
+		delete(v.transitionProbabilities, key)
+	}
+	v.SetTransitionFunc(func(from, to State) (float64, bool) {
+		val, ok := transitions[TransitionHash{from.ID(), to.ID()}]
+		return val, ok
+	})
+	vpath, err := v.EvalPath()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if vpath.Probability != correctPath.Probability {
+		t.Error(
+			"Probability has to be", correctPath.Probability, ", but got", vpath.Probability,
+		)
+	}
+
+	// Forbidden transitions do not fall back to default probability
+	v.SetDefaultTransitionProbability(1.0)
+	v.SetTransitionFunc(func(from, to State) (float64, bool) {
+		return 0, false
+	})
+	if _, err := v.EvalPath(); !errors.Is(err, ErrPathBroken) {
+		t.Error(
+			"Error has to be ErrPathBroken, but got", err,
+		)
+	}
+
+	// Stored transition takes precedence over the function
+	v.PutTransitionProbability(incStates[0], incStates[0], 1.0)
+	vpath, err = v.EvalPath()
+	if err != nil {
+		t.Fatal(err)
+	}
+	for i := range vpath.Path {
+		if vpath.Path[i].ID() != incStates[0].ID() {
+			t.Error(
+				"State on position", i, "has to be", incStates[0], ", but got", vpath.Path[i],
+			)
+		}
+	}
+}
+
+func TestViterbiPredecessors(t *testing.T) {
+	v, incStates, _ := healthModel()
+	correctPath, err := v.EvalPath()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Listing every state is the same as full scan
+	v.SetPredecessors(incStates[0], []State{incStates[0], incStates[1]})
+	v.SetPredecessors(incStates[1], []State{incStates[1], incStates[0]})
+	vpath, err := v.EvalPath()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if vpath.Probability != correctPath.Probability {
+		t.Error(
+			"Probability has to be", correctPath.Probability, ", but got", vpath.Probability,
+		)
+	}
+
+	// 'Fever' could be reached from 'Fever' only, so the path can't switch from 'Healty' to 'Fever'
+	v.SetPredecessors(incStates[1], []State{incStates[1]})
+	vpath, err = v.EvalPath()
+	if err != nil {
+		t.Fatal(err)
+	}
+	for i := 1; i < len(vpath.Path); i++ {
+		if vpath.Path[i-1].ID() == incStates[0].ID() && vpath.Path[i].ID() == incStates[1].ID() {
+			t.Error(
+				"Path has not to contain transition from 'Healty' to 'Fever', but got", vpath.Path,
+			)
+		}
+	}
+	forward, err := v.Forward()
+	if err != nil {
+		t.Fatal(err)
+	}
+	full, _, _ := healthModel()
+	fullForward, err := full.Forward()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if forward >= fullForward {
+		t.Error(
+			"Restricted forward probability has to be less than", fullForward, ", but got", forward,
+		)
+	}
+
+	v.SetPredecessors(incStates[1], nil)
+	v.RemoveState(incStates[1])
+	if preds := v.predecessors[incStates[0].ID()]; len(preds) != 1 || preds[0].ID() != incStates[0].ID() {
+		t.Error(
+			"Removed state has to be removed from predecessors, but got", preds,
+		)
+	}
+}
+
+func TestViterbiExpectedActiveStates(t *testing.T) {
+	v := randomModel(30, 50, 3)
+	correctPath, err := v.EvalPath()
+	if err != nil {
+		t.Fatal(err)
+	}
+	v.SetExpectedActiveStates(30)
+	vpath, err := v.EvalPath()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if vpath.Probability != correctPath.Probability {
+		t.Error(
+			"Probability has to be", correctPath.Probability, ", but got", vpath.Probability,
+		)
+	}
+	for i := range correctPath.Path {
+		if vpath.Path[i].ID() != correctPath.Path[i].ID() {
+			t.Error(
+				"State on position", i, "has to be", correctPath.Path[i], ", but got", vpath.Path[i],
+			)
+		}
+	}
+}
+
+func TestViterbiUnknownEmission(t *testing.T) {
+	v, incStates, _ := healthModel()
+	unknown := CustomObservation{Name: "unknown", id: 4}
+	v.AddObservation(unknown)
+	if _, err := v.EvalPath(); !errors.Is(err, ErrPathBroken) {
+		t.Fatal(
+			"Error has to be ErrPathBroken, but got", err,
+		)
+	}
+
+	v.SetUnknownEmission(0.5)
+	vpath, err := v.EvalPath()
+	if err != nil {
+		t.Fatal(err)
+	}
+	// The same emission for every state keeps the best path of known observations: 0.01512·max(0.4·0.5, 0.6·0.5)
+	if math.Abs(vpath.Probability-0.01512*0.3) > 1e-12 {
+		t.Error(
+			"Probability has to be", 0.01512*0.3, ", but got", vpath.Probability,
+		)
+	}
+	parallelPath, err := v.EvalPathParallel(2)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if parallelPath.String() != vpath.String() {
+		t.Error(
+			"Parallel path has to be", vpath, ", but got", parallelPath,
+		)
+	}
+	// Every path is scaled by the same emission
+	forwardProb, err := v.Forward()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if math.Abs(forwardProb-0.03628*0.5) > 1e-12 {
+		t.Error(
+			"Forward probability has to be", 0.03628*0.5, ", but got", forwardProb,
+		)
+	}
+
+	// Observation known to a single state only is not unknown
+	v.PutEmissionProbability(incStates[0], unknown, 0.5)
+	vpath, err = v.EvalPath()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if vpath.Path[len(vpath.Path)-1].ID() != incStates[0].ID() {
+		t.Error(
+			"Only 'Healty' could emit known observation, but got", vpath.Path[len(vpath.Path)-1],
+		)
+	}
+}
+
+func TestViterbiWeights(t *testing.T) {
+	v, incStates, _ := healthModel()
+	v.SetEmissionWeight(1)
+	v.SetTransitionWeight(1)
+	vpath, err := v.EvalPath()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if math.Abs(vpath.Probability-0.01512) > 1e-12 {
+		t.Error(
+			"Unit weights have to keep probability 0.01512, but got", vpath.Probability,
+		)
+	}
+
+	// Emissions are ignored: the path follows start and transition probabilities only
+	v.SetEmissionWeight(0)
+	vpath, err = v.EvalPath()
+	if err != nil {
+		t.Fatal(err)
+	}
+	for i := range vpath.Path {
+		if vpath.Path[i] != incStates[0] {
+			t.Error(
+				"State on position", i, "has to be", incStates[0], ", but got", vpath.Path[i],
+			)
+		}
+	}
+	if math.Abs(vpath.Probability-0.6*0.7*0.7) > 1e-12 {
+		t.Error(
+			"Probability has to be", 0.6*0.7*0.7, ", but got", vpath.Probability,
+		)
+	}
+
+	// Logarithmic probabilities are multiplied by weights
+	v.SetEmissionWeight(2)
+	v.SetTransitionWeight(0.5)
+	vpath, err = v.EvalPath()
+	if err != nil {
+		t.Fatal(err)
+	}
+	logPath, err := v.ToLog().EvalPathLogProbabilities()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if math.Abs(logPath.Probability-vpath.LogProbability) > 1e-9 {
+		t.Error(
+			"Logarithmic probability has to be", vpath.LogProbability, ", but got", logPath.Probability,
+		)
+	}
+}
+
+func TestViterbiStrictTransitions(t *testing.T) {
+	v, incStates, _ := healthModel()
+	v.SetStrictTransitions(true)
+	if _, err := v.EvalPath(); err != nil {
+		t.Fatal(err)
+	}
+
+	delete(v.transitionProbabilities, TransitionHash{incStates[1].ID(), incStates[0].ID()})
+	_, err := v.EvalPath()
+	if !errors.Is(err, ErrMissingTransition) {
+		t.Fatal(
+			"Error has to be ErrMissingTransition, but got", err,
+		)
+	}
+	if msg := err.Error(); msg != "missing transition probability: from state {Fever 2} to state {Healty 1}" {
+		t.Error(
+			"Error has to name both states, but got", msg,
+		)
+	}
+
+	v.SetPredecessors(incStates[0], []State{incStates[0]})
+	if _, err := v.EvalPath(); err != nil {
+		t.Error(
+			"Transitions excluded by predecessors have not to be evaluated, but got", err,
+		)
+	}
+	v.SetPredecessors(incStates[0], nil)
+
+	v.SetDefaultTransitionProbability(0.1)
+	if _, err := v.EvalPath(); err != nil {
+		t.Error(
+			"Default transition has not to be missing, but got", err,
+		)
+	}
+
+	v, incStates, _ = healthModel()
+	delete(v.transitionProbabilities, TransitionHash{incStates[1].ID(), incStates[0].ID()})
+	if _, err := v.EvalPath(); err != nil {
+		t.Error(
+			"Missing transition has to be impossible by default, but got", err,
+		)
+	}
+}
+
+func TestViterbiForbidTransition(t *testing.T) {
+	v, incStates, _ := healthModel()
+	healthy, fever := incStates[0], incStates[1]
+	delete(v.transitionProbabilities, TransitionHash{healthy.ID(), fever.ID()})
+	delete(v.transitionProbabilities, TransitionHash{fever.ID(), fever.ID()})
+	// Explicit zero is not filled by default probability, while unset pair is
+	v.PutTransitionProbability(fever, fever, 0)
+	v.SetDefaultTransitionProbability(0.3)
+	if val, ok, _ := v.transitionProbability(fever, fever, false); !ok || val != 0 {
+		t.Error(
+			"Explicit zero has to be kept, but got", val, ok,
+		)
+	}
+	if val, _, _ := v.transitionProbability(healthy, fever, false); val != 0.3 {
+		t.Error(
+			"Unset transition has to be 0.3, but got", val,
+		)
+	}
+
+	v.ForbidTransition(healthy, fever)
+	v.SetTransitionFunc(func(from, to State) (float64, bool) {
+		return 1, true
+	})
+	v.SetStrictTransitions(true)
+	vpath, err := v.EvalPath()
+	if err != nil {
+		t.Fatal(err)
+	}
+	// 'Fever' could be reached neither from 'Healty' nor from itself
+	for i := 1; i < len(vpath.Path); i++ {
+		if vpath.Path[i] != healthy {
+			t.Error(
+				"State", i, "has to be", healthy, "but got", vpath.Path[i],
+			)
+		}
+	}
+	if val, ok, _ := v.ToLog().transitionProbability(healthy, fever, true); !ok || !math.IsInf(val, -1) {
+		t.Error(
+			"Forbidden transition has to be -Inf in logarithmic evaluation, but got", val, ok,
+		)
+	}
+
+	v.RemoveState(fever)
+	if len(v.forbiddenTransitions) != 0 {
+		t.Error(
+			"Forbidden transitions of removed state have to be removed, but got", v.forbiddenTransitions,
+		)
+	}
+}
+
+func TestViterbiEmissionsAreDensities(t *testing.T) {
+	v, _, _ := healthModel()
+	for key, val := range v.emissionProbabilities {
+		v.emissionProbabilities[key] = val * 10
+	}
+	if _, err := v.EvalPath(); !errors.Is(err, ErrInvalidProbability) {
+		t.Fatal(
+			"Error has to be ErrInvalidProbability, but got", err,
+		)
+	}
+
+	v.SetEmissionsAreDensities(true)
+	if err := v.ValidateModel(); err != nil {
+		t.Error(
+			"Densities above 1 have to be valid, but got", err,
+		)
+	}
+	vpath, err := v.EvalPath()
+	if err != nil {
+		t.Fatal(err)
+	}
+	// Every emission of the path is scaled by 10
+	if fmt.Sprint(vpath.IDs()) != "[1 1 2]" || math.Abs(vpath.Probability-15.12) > 1e-9 {
+		t.Error(
+			"Path has to be '15.12: [1 1 2]', but got", vpath,
+		)
+	}
+
+	for key := range v.emissionProbabilities {
+		v.emissionProbabilities[key] = -1
+	}
+	if _, err := v.EvalPath(); !errors.Is(err, ErrInvalidProbability) {
+		t.Error(
+			"Negative density has to be rejected, but got", err,
+		)
+	}
+}
+
+func TestViterbiSetTieBreaker(t *testing.T) {
+	v := New()
+	a, b := CustomState{Name: "a", id: 1}, CustomState{Name: "b", id: 2}
+	obs := CustomObservation{Name: "o", id: 1}
+	for _, st := range []CustomState{a, b} {
+		v.AddState(st)
+		v.PutStartProbability(st, 0.5)
+		v.PutEmissionProbability(st, obs, 1)
+		for _, to := range []CustomState{a, b} {
+			v.PutTransitionProbability(st, to, 0.5)
+		}
+	}
+	v.AddObservation(obs)
+	v.AddObservation(obs)
+
+	// Every path is equally probable
+	vpath, err := v.EvalPath()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if fmt.Sprint(vpath.IDs()) != "[1 1]" {
+		t.Error(
+			"State with the lowest ID has to win by default, but got", vpath,
+		)
+	}
+
+	v.SetTieBreaker(func(a, b State) bool {
+		return a.ID() > b.ID()
+	})
+	evaluators := map[string]func() (ViterbiPath, error){
+		"EvalPath":        v.EvalPath,
+		"EvalPathCompact": v.EvalPathCompact,
+		"EvalPathN": func() (ViterbiPath, error) {
+			paths, err := v.EvalPathN(1)
+			if err != nil {
+				return ViterbiPath{}, err
+			}
+			return paths[0], nil
+		},
+	}
+	for name, eval := range evaluators {
+		vpath, err := eval()
+		if err != nil {
+			t.Fatal(name, err)
+		}
+		if fmt.Sprint(vpath.IDs()) != "[2 2]" {
+			t.Error(
+				name, "has to prefer state with the highest ID, but got", vpath,
+			)
+		}
+	}
+
+	v.SetTieBreaker(nil)
+	if vpath, _ := v.EvalPath(); fmt.Sprint(vpath.IDs()) != "[1 1]" {
+		t.Error(
+			"Default rule has to be restored, but got", vpath,
+		)
+	}
+}
+
+func TestViterbiSetTransitionOrientation(t *testing.T) {
+	v, _, _ := healthModel()
+	expected, err := v.EvalPath()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Transitions are stored transposed: P(from|to)
+	transposed := make(map[TransitionHash]float64, len(v.transitionProbabilities))
+	for key, val := range v.transitionProbabilities {
+		transposed[TransitionHash{From: key.To, To: key.From}] = val
+	}
+	v.transitionProbabilities = transposed
+	vpath, err := v.EvalPath()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if vpath.String() == expected.String() {
+		t.Fatal(
+			"Transposed transitions have to change the path, but got", vpath,
+		)
+	}
+
+	v.SetTransitionOrientation(TransitionToFrom)
+	vpath, err = v.EvalPath()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if vpath.String() != expected.String() {
+		t.Error(
+			"Path has to be", expected, ", but got", vpath,
+		)
+	}
+}
+
+func TestViterbiTransitionOrientationEstimation(t *testing.T) {
+	v, incStates, incomingObservations := healthModel()
+	expected, err := v.EvalPath()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Raw transposed counts: Normalize has to make every column sum to 1
+	transposed, _, _ := healthModel()
+	transposed.transitionProbabilities = make(map[TransitionHash]float64, len(v.transitionProbabilities))
+	for key, val := range v.transitionProbabilities {
+		transposed.transitionProbabilities[TransitionHash{From: key.To, To: key.From}] = 10 * val
+	}
+	transposed.SetTransitionOrientation(TransitionToFrom)
+	if err := transposed.Normalize(); err != nil {
+		t.Fatal(err)
+	}
+	vpath, err := transposed.EvalPath()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if vpath.String() != expected.String() || math.Abs(vpath.Probability-expected.Probability) > 1e-12 {
+		t.Error(
+			"Path has to be", expected, ", but got", vpath,
+		)
+	}
+
+	// Sampling has to follow the same transitions as for the original model
+	states, observations, err := v.Sample(50, rand.New(rand.NewSource(1)))
+	if err != nil {
+		t.Fatal(err)
+	}
+	transposedStates, transposedObservations, err := transposed.Sample(50, rand.New(rand.NewSource(1)))
+	if err != nil {
+		t.Fatal(err)
+	}
+	for i := range states {
+		if states[i] != transposedStates[i] || observations[i] != transposedObservations[i] {
+			t.Fatal(
+				"Sampled sequences have to match at", i, ", but got", states[i], observations[i], "and", transposedStates[i], transposedObservations[i],
+			)
+		}
+	}
+
+	// Fitted transitions have to be looked up the same way as evaluators do
+	pairs := []LabeledSequence{
+		{
+			States:       []State{incStates[0], incStates[1], incStates[1]},
+			Observations: []Observation{incomingObservations[0], incomingObservations[1], incomingObservations[2]},
+		},
+	}
+	fitted := New()
+	fitted.SetTransitionOrientation(TransitionToFrom)
+	if err := fitted.Fit(pairs, 0); err != nil {
+		t.Fatal(err)
+	}
+	if val := fitted.transitionProbabilities[fitted.transitionKey(incStates[0], incStates[1])]; val != 1 {
+		t.Error(
+			"Transition probability from 'Healty' to 'Fever' has to be 1, but got", val,
+		)
+	}
+	fitted.IncTransition(incStates[1], incStates[0])
+	if err := fitted.Finalize(0); err != nil {
+		t.Fatal(err)
+	}
+	if val := fitted.transitionProbabilities[fitted.transitionKey(incStates[1], incStates[0])]; val != 1 {
+		t.Error(
+			"Transition probability from 'Fever' to 'Healty' has to be 1, but got", val,
+		)
+	}
+}
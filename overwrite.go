@@ -0,0 +1,44 @@
+package viterbi
+
+import (
+	"errors"
+	"fmt"
+)
+
+// ErrProbabilityOverwrite is recorded when probability is put again with different value (see SetOverwritePolicy)
+var ErrProbabilityOverwrite = errors.New("probability has been put again with different value")
+
+// OverwritePolicy defines how model reacts to probability put again with different value
+type OverwritePolicy int
+
+const (
+	// OverwriteSilent does nothing (default)
+	OverwriteSilent OverwritePolicy = iota
+	// OverwriteWarn records conflict: recorded ones are available via Overwrites and reported by ValidateModel
+	OverwriteWarn
+	// OverwriteError records conflict the same way as OverwriteWarn and makes evaluation fail with *ValidationError until Reset
+	OverwriteError
+)
+
+// SetOverwritePolicy sets how Put* methods (start, end, emission, transition and second-order transition probabilities) react
+// to probability put again for the same key with different value. Stored value is resolved as usual regardless of the policy:
+// start and end probabilities are replaced, emission and transition ones are kept.
+// Default policy is OverwriteSilent
+func (m *Model) SetOverwritePolicy(policy OverwritePolicy) {
+	m.overwritePolicy = policy
+}
+
+// Overwrites returns conflicts recorded according to overwrite policy (see SetOverwritePolicy) in order of Put* calls.
+// Every returned error matches ErrProbabilityOverwrite
+func (m *Model) Overwrites() []error {
+	return append([]error{}, m.overwrites...)
+}
+
+// recordOverwrite records conflict when policy requires it and probability of the key has been put already with different value
+func (m *Model) recordOverwrite(prev float64, exists bool, val float64, format string, args ...interface{}) {
+	if m.overwritePolicy == OverwriteSilent || !exists || prev == val {
+		return
+	}
+	args = append(append([]interface{}{ErrProbabilityOverwrite}, args...), prev, val)
+	m.overwrites = append(m.overwrites, fmt.Errorf("%w: "+format+": %v, then %v", args...))
+}
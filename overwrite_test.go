@@ -0,0 +1,56 @@
+package viterbi
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestViterbiSetOverwritePolicy(t *testing.T) {
+	v, incStates, incomingObservations := healthModel()
+	healthy, fever := incStates[0], incStates[1]
+
+	// Silent policy (default) does not record anything
+	v.PutTransitionProbability(healthy, fever, 0.5)
+	if len(v.Overwrites()) != 0 {
+		t.Error(
+			"Expected no recorded overwrites, but got", v.Overwrites(),
+		)
+	}
+
+	v.SetOverwritePolicy(OverwriteWarn)
+	// The same value is not a conflict
+	v.PutTransitionProbability(healthy, fever, 0.3)
+	v.PutTransitionProbability(healthy, fever, 0.5)
+	v.PutEmissionProbability(healthy, incomingObservations[0], 0.9)
+	v.PutStartProbability(healthy, 0.6)
+	overwrites := v.Overwrites()
+	if len(overwrites) != 2 || !errors.Is(overwrites[0], ErrProbabilityOverwrite) {
+		t.Fatal(
+			"Expected 2 recorded overwrites, but got", overwrites,
+		)
+	}
+	if _, err := v.EvalPath(); err != nil {
+		t.Error(
+			"Warning policy has not to fail evaluation, but got", err,
+		)
+	}
+	if err := v.ValidateModel(); !errors.Is(err, ErrProbabilityOverwrite) {
+		t.Error(
+			"Recorded overwrites have to be reported by ValidateModel, but got", err,
+		)
+	}
+
+	v.SetOverwritePolicy(OverwriteError)
+	if _, err := v.EvalPath(); !errors.Is(err, ErrProbabilityOverwrite) {
+		t.Error(
+			"Error policy has to fail evaluation, but got", err,
+		)
+	}
+
+	v.Reset()
+	if len(v.Overwrites()) != 0 {
+		t.Error(
+			"Recorded overwrites have to be cleared by Reset, but got", v.Overwrites(),
+		)
+	}
+}
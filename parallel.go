@@ -0,0 +1,69 @@
+package viterbi
+
+import (
+	"context"
+	"sync"
+)
+
+// EvalPathParallel is the same as EvalPath, but every trellis column is evaluated by given number of goroutines.
+// Cells of the column are independent given previous column, so output is identical to EvalPath (including tie-breaking).
+// Number of workers less than 2 falls back to sequential evaluation
+// When every probability is in [0;1]
+func (v *Viterbi) EvalPathParallel(workers int) (ViterbiPath, error) {
+	return v.evalPathParallel(workers, false)
+}
+
+// EvalPathLogProbabilitiesParallel is the same as EvalPathParallel, but when every probability is logarithmic
+func (v *Viterbi) EvalPathLogProbabilitiesParallel(workers int) (ViterbiPath, error) {
+	return v.evalPathParallel(workers, true)
+}
+
+func (v *Viterbi) evalPathParallel(workers int, logSpace bool) (ViterbiPath, error) {
+	V, logScale, err := v.evalTrellis(context.Background(), evalOptions{logSpace: logSpace, workers: workers})
+	if err != nil {
+		return ViterbiPath{}, err
+	}
+	return v.backtrack(V, logSpace, logScale)
+}
+
+// evalColumnParallel evaluates trellis column splitting states into chunks between workers
+func (v *Viterbi) evalColumnParallel(prev denseColumn, t int, unknown bool, opts evalOptions) (map[State]ViterbiVal, error) {
+	type cell struct {
+		value ViterbiVal
+		ok    bool
+		err   error
+	}
+	cells := make([]cell, len(v.states))
+	chunk := (len(v.states) + opts.workers - 1) / opts.workers
+	wg := sync.WaitGroup{}
+	for from := 0; from < len(v.states); from += chunk {
+		to := from + chunk
+		if to > len(v.states) {
+			to = len(v.states)
+		}
+		wg.Add(1)
+		go func(from, to int) {
+			defer wg.Done()
+			for i := from; i < to; i++ {
+				value, ok, err := v.evalCell(prev, v.states[i], t, unknown, opts)
+				cells[i] = cell{value: value, ok: ok, err: err}
+				if err != nil {
+					return
+				}
+			}
+		}(from, to)
+	}
+	wg.Wait()
+
+	// Merge sequentially in order of states, so the same error is reported as in sequential evaluation
+	column := v.newColumn()
+	for i := range cells {
+		if cells[i].err != nil {
+			return nil, cells[i].err
+		}
+		if cells[i].ok {
+			column[v.states[i]] = cells[i].value
+		}
+	}
+	return column, nil
+}
@@ -0,0 +1,61 @@
+package viterbi
+
+import (
+	"math/rand"
+	"testing"
+)
+
+// randomModel returns dense model with random probabilities
+func randomModel(statesNum, observationsNum int, seed int64) *Viterbi {
+	rng := rand.New(rand.NewSource(seed))
+	v := New()
+	states := make([]CustomState, statesNum)
+	for i := range states {
+		states[i] = CustomState{Name: "s", id: i}
+		v.AddState(states[i])
+	}
+	observations := make([]CustomObservation, observationsNum)
+	for i := range observations {
+		observations[i] = CustomObservation{Name: "o", id: i}
+		v.AddObservation(observations[i])
+	}
+	for i := range states {
+		v.PutStartProbability(states[i], rng.Float64())
+		for j := range observations {
+			v.PutEmissionProbability(states[i], observations[j], rng.Float64())
+		}
+		for j := range states {
+			v.PutTransitionProbability(states[i], states[j], rng.Float64())
+		}
+	}
+	if err := v.Normalize(); err != nil {
+		panic(err)
+	}
+	return v
+}
+
+func TestViterbiEvalPathParallel(t *testing.T) {
+	v := randomModel(30, 20, 42)
+	expected, err := v.EvalPath()
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, workers := range []int{0, 1, 3, 8, 64} {
+		vpath, err := v.EvalPathParallel(workers)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if vpath.Probability != expected.Probability {
+			t.Error(
+				"Probability with", workers, "workers has to be", expected.Probability, "but got", vpath.Probability,
+			)
+		}
+		for i := range expected.Path {
+			if vpath.Path[i] != expected.Path[i] {
+				t.Error(
+					"State", i, "with", workers, "workers has to be", expected.Path[i], "but got", vpath.Path[i],
+				)
+			}
+		}
+	}
+}
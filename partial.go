@@ -0,0 +1,36 @@
+package viterbi
+
+import (
+	"context"
+	"errors"
+)
+
+// EvalPathPartial is the same as EvalPath, but when path is broken at some observation the best path over preceding observations is returned
+// instead of *PathBrokenError. Second return value is number of decoded observations: it's less than number of observations when path is truncated.
+// End probabilities are not applied to truncated path. Error is returned when path is broken at the first observation
+// When every probability is in [0;1]
+func (v *Viterbi) EvalPathPartial() (ViterbiPath, int, error) {
+	return v.evalPathPartial(false)
+}
+
+// EvalPathPartialLogProbabilities is the same as EvalPathPartial, but when every probability is logarithmic
+func (v *Viterbi) EvalPathPartialLogProbabilities() (ViterbiPath, int, error) {
+	return v.evalPathPartial(true)
+}
+
+func (v *Viterbi) evalPathPartial(logSpace bool) (ViterbiPath, int, error) {
+	V, logScale, err := v.evalTrellis(context.Background(), evalOptions{logSpace: logSpace})
+	var brokenErr *PathBrokenError
+	if errors.As(err, &brokenErr) && brokenErr.ObservationIndex > 0 {
+		truncated := *v
+		truncated.observations = v.observations[:brokenErr.ObservationIndex]
+		truncated.endProbabilities = nil
+		path, err := truncated.backtrack(V, logSpace, logScale)
+		return path, len(V), err
+	}
+	if err != nil {
+		return ViterbiPath{}, 0, err
+	}
+	path, err := v.backtrack(V, logSpace, logScale)
+	return path, len(V), err
+}
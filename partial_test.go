@@ -0,0 +1,60 @@
+package viterbi
+
+import (
+	"errors"
+	"math"
+	"testing"
+)
+
+func TestViterbiEvalPathPartial(t *testing.T) {
+	v, _, incomingObservations := healthModel()
+	vpath, decoded, err := v.EvalPathPartial()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if decoded != len(incomingObservations) || vpath.Probability != 0.01512 {
+		t.Error(
+			"Whole path has to be decoded with probability 0.01512, but got", decoded, vpath.Probability,
+		)
+	}
+
+	// No state could emit unknown observation: path is truncated before it
+	v.AddObservation(CustomObservation{Name: "unknown", id: 4})
+	v.AddObservation(incomingObservations[0])
+	if _, err := v.EvalPath(); !errors.Is(err, ErrPathBroken) {
+		t.Fatal(
+			"Error has to be ErrPathBroken, but got", err,
+		)
+	}
+	vpath, decoded, err = v.EvalPathPartial()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if decoded != 3 {
+		t.Error(
+			"Expected 3 decoded observations, but got:", decoded,
+		)
+	}
+	if vpath.String() != "0.01512: [1 1 2]" {
+		t.Error(
+			"Path has to be '0.01512: [1 1 2]', but got", vpath,
+		)
+	}
+	logPath, decoded, err := logModel(v).EvalPathPartialLogProbabilities()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if decoded != 3 || math.Abs(logPath.Probability-math.Log(0.01512)) > 1e-12 {
+		t.Error(
+			"Logarithmic probability of 3 observations has to be", math.Log(0.01512), ", but got", decoded, logPath.Probability,
+		)
+	}
+
+	v.ResetObservations()
+	v.AddObservation(CustomObservation{Name: "unknown", id: 4})
+	if _, _, err := v.EvalPathPartial(); !errors.Is(err, ErrPathBroken) {
+		t.Error(
+			"Error has to be ErrPathBroken when the first observation breaks path, but got", err,
+		)
+	}
+}
@@ -0,0 +1,51 @@
+package viterbi
+
+import (
+	"fmt"
+	"strings"
+)
+
+// IDs returns IDs of path states
+func (p ViterbiPath) IDs() []int {
+	ids := make([]int, len(p.Path))
+	for i, st := range p.Path {
+		ids[i] = st.ID()
+	}
+	return ids
+}
+
+// EvalPathIDs is the same as EvalPath, but returns IDs of path states and probability of the path only
+func (v *Viterbi) EvalPathIDs() ([]int, float64, error) {
+	vpath, err := v.EvalPath()
+	if err != nil {
+		return nil, 0, err
+	}
+	return vpath.IDs(), vpath.Probability, nil
+}
+
+// String renders probability and IDs of path states, e.g. "0.01512: [1 1 2]"
+func (p ViterbiPath) String() string {
+	return p.StringWith(func(st State) string {
+		return fmt.Sprint(st.ID())
+	})
+}
+
+// LabelsByID returns labeler rendering states by given labels keyed by ID(), e.g. for StringWith, PrintTrellisWith, TrellisDOTWith or WriteCSVWith.
+// States without label are rendered by ID()
+func LabelsByID(labels map[int]string) func(st State) string {
+	return func(st State) string {
+		if label, ok := labels[st.ID()]; ok {
+			return label
+		}
+		return fmt.Sprint(st.ID())
+	}
+}
+
+// StringWith is the same as String, but every state is rendered by fn
+func (p ViterbiPath) StringWith(fn func(st State) string) string {
+	parts := make([]string, len(p.Path))
+	for i, st := range p.Path {
+		parts[i] = fn(st)
+	}
+	return fmt.Sprintf("%v: [%s]", p.Probability, strings.Join(parts, " "))
+}
@@ -0,0 +1,84 @@
+package viterbi
+
+import (
+	"fmt"
+	"testing"
+)
+
+func TestViterbiPathString(t *testing.T) {
+	v, _, _ := healthModel()
+	vpath, err := v.EvalPath()
+	if err != nil {
+		t.Fatal(err)
+	}
+	ids := vpath.IDs()
+	correctIDs := []int{1, 1, 2}
+	if len(ids) != len(correctIDs) {
+		t.Fatal(
+			"Number of IDs has to be", len(correctIDs), ", but got", len(ids),
+		)
+	}
+	for i := range correctIDs {
+		if ids[i] != correctIDs[i] {
+			t.Error(
+				"ID on position", i, "has to be", correctIDs[i], ", but got", ids[i],
+			)
+		}
+	}
+	if str := vpath.String(); str != "0.01512: [1 1 2]" {
+		t.Error(
+			"String has to be '0.01512: [1 1 2]', but got", str,
+		)
+	}
+	if str := fmt.Sprint(vpath); str != vpath.String() {
+		t.Error(
+			"Path has to be printed via String, but got", str,
+		)
+	}
+	named := vpath.StringWith(func(st State) string {
+		return st.(CustomState).Name
+	})
+	if named != "0.01512: [Healty Healty Fever]" {
+		t.Error(
+			"String has to be '0.01512: [Healty Healty Fever]', but got", named,
+		)
+	}
+}
+
+func TestViterbiEvalPathIDs(t *testing.T) {
+	v, _, _ := healthModel()
+	ids, prob, err := v.EvalPathIDs()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if fmt.Sprint(ids) != "[1 1 2]" {
+		t.Error(
+			"IDs have to be [1 1 2], but got", ids,
+		)
+	}
+	if prob != 0.01512 {
+		t.Error(
+			"Probability has to be 0.01512, but got", prob,
+		)
+	}
+
+	if _, _, err := New().EvalPathIDs(); err != ErrNoStates {
+		t.Error(
+			"Error has to be ErrNoStates, but got", err,
+		)
+	}
+}
+
+func TestLabelsByID(t *testing.T) {
+	v, _, _ := healthModel()
+	vpath, err := v.EvalPath()
+	if err != nil {
+		t.Fatal(err)
+	}
+	// 'Fever' has no label, so it's rendered by ID
+	if s := vpath.StringWith(LabelsByID(map[int]string{1: "Healthy"})); s != "0.01512: [Healthy Healthy 2]" {
+		t.Error(
+			"Path has to be '0.01512: [Healthy Healthy 2]', but got", s,
+		)
+	}
+}
@@ -0,0 +1,140 @@
+package viterbi
+
+import (
+	"errors"
+	"fmt"
+	"math"
+)
+
+// ErrInvalidTimestep is returned when timestep is out of observations range
+var ErrInvalidTimestep = errors.New("timestep is out of observations range")
+
+// EvalPosterior evaluates sequence of individually most probable states (posterior decoding).
+// For every observation it chooses state with maximum normalized γ[t][s] = α[t][s]·β[t][s].
+// Probability of returned path is product of chosen marginals.
+// Note: since states are chosen independently, returned path could contain transitions which are impossible in the model.
+// When every probability is in [0;1]
+// Equal probabilities are resolved according to the tie-breaker (see SetTieBreaker), in favour of the state with the lowest ID() by default
+func (v *Viterbi) EvalPosterior() (ViterbiPath, error) {
+	return v.evalPosterior(false)
+}
+
+// EvalPosteriorLogProbabilities is the same as EvalPosterior, but when every probability is logarithmic.
+// Probability of returned path is sum of chosen logarithmic marginals
+func (v *Viterbi) EvalPosteriorLogProbabilities() (ViterbiPath, error) {
+	return v.evalPosterior(true)
+}
+
+func (v *Viterbi) evalPosterior(logSpace bool) (ViterbiPath, error) {
+	gamma, err := v.posterior(logSpace)
+	if err != nil {
+		return ViterbiPath{}, err
+	}
+	return v.posteriorPath(gamma, logSpace), nil
+}
+
+// posteriorPath chooses the most probable state for every observation given posterior probabilities
+func (v *Viterbi) posteriorPath(gamma []map[State]float64, logSpace bool) ViterbiPath {
+	path := ViterbiPath{
+		Probability: one(logSpace),
+		Path:        make([]State, len(gamma)),
+	}
+	for t := range gamma {
+		var best State
+		for _, st := range v.states {
+			prob, ok := gamma[t][st]
+			if !ok {
+				continue
+			}
+			if best == nil || v.preferState(prob, st, gamma[t][best], best) {
+				best = st
+			}
+		}
+		path.Path[t] = best
+		path.Probability = combine(logSpace, path.Probability, gamma[t][best])
+	}
+	path.LogProbability = logProbability(logSpace, path.Probability)
+	return path
+}
+
+// posterior evaluates normalized posterior probabilities γ[t][s] of being in the state s for every observation t
+func (v *Viterbi) posterior(logSpace bool) ([]map[State]float64, error) {
+	alpha, err := v.forward(logSpace)
+	if err != nil {
+		return nil, err
+	}
+	beta, err := v.backward(logSpace)
+	if err != nil {
+		return nil, err
+	}
+	gamma := make([]map[State]float64, len(alpha))
+	for t := range alpha {
+		gamma[t], err = v.posteriorColumn(alpha[t], beta[t], t, logSpace)
+		if err != nil {
+			return nil, err
+		}
+	}
+	return gamma, nil
+}
+
+// posteriorColumn evaluates normalized posterior probabilities of single observation given its forward and backward variables
+func (v *Viterbi) posteriorColumn(alpha, beta map[State]float64, t int, logSpace bool) (map[State]float64, error) {
+	gamma := make(map[State]float64)
+	for _, st := range v.states {
+		alphaProb, ok := alpha[st]
+		if !ok {
+			continue
+		}
+		betaProb, ok := beta[st]
+		if !ok {
+			continue
+		}
+		gamma[st] = combine(logSpace, alphaProb, betaProb)
+	}
+	total := sumProbabilities(logSpace, v.columnValues(gamma)...)
+	if len(gamma) == 0 || total == 0 || math.IsInf(total, -1) {
+		return nil, v.pathBroken(t)
+	}
+	for st := range gamma {
+		gamma[st] = divide(logSpace, gamma[st], total)
+	}
+	return gamma, nil
+}
+
+// PosteriorAt evaluates normalized posterior probabilities γ[t][s] of being in every state at observation t only:
+// forward variables are evaluated up to t and backward ones back to t. States which can't be passed at t are omitted
+// When every probability is in [0;1]
+func (v *Viterbi) PosteriorAt(t int) (map[State]float64, error) {
+	return v.posteriorAt(t, false)
+}
+
+// PosteriorAtLogProbabilities is the same as PosteriorAt, but when every probability is logarithmic (returned ones are logarithmic too)
+func (v *Viterbi) PosteriorAtLogProbabilities(t int) (map[State]float64, error) {
+	return v.posteriorAt(t, true)
+}
+
+func (v *Viterbi) posteriorAt(t int, logSpace bool) (map[State]float64, error) {
+	if err := v.validate(); err != nil {
+		return nil, err
+	}
+	if t < 0 || t >= len(v.observations) {
+		return nil, fmt.Errorf("%w: %d for %d observations", ErrInvalidTimestep, t, len(v.observations))
+	}
+	alpha, err := v.forwardUntil(logSpace, t)
+	if err != nil {
+		return nil, err
+	}
+	beta, err := v.backwardFrom(logSpace, t)
+	if err != nil {
+		return nil, err
+	}
+	return v.posteriorColumn(alpha[t], beta[t], t, logSpace)
+}
+
+// divide normalizes probability: quotient for classic probabilities and difference for logarithmic ones
+func divide(logSpace bool, a, b float64) float64 {
+	if logSpace {
+		return a - b
+	}
+	return a / b
+}
@@ -0,0 +1,97 @@
+package viterbi
+
+import (
+	"errors"
+	"math"
+	"testing"
+)
+
+func TestViterbiEvalPosterior(t *testing.T) {
+	v, incStates, incomingObservations := healthModel()
+
+	vpath, err := v.EvalPosterior()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(vpath.Path) != len(incomingObservations) {
+		t.Fatal(
+			"Expected", len(incomingObservations), "states, but got:", len(vpath.Path),
+		)
+	}
+	// Marginals of the classic example: Healthy wins on first two days and Fever on the third one
+	expected := []State{incStates[0], incStates[0], incStates[1]}
+	for i := range expected {
+		if vpath.Path[i] != expected[i] {
+			t.Error(
+				"State", i, "has to be", expected[i], "but got", vpath.Path[i],
+			)
+		}
+	}
+	if vpath.Probability <= 0 || vpath.Probability > 1 {
+		t.Error(
+			"Probability has to be in (0;1], but got", vpath.Probability,
+		)
+	}
+
+	logPath, err := logModel(v).EvalPosteriorLogProbabilities()
+	if err != nil {
+		t.Fatal(err)
+	}
+	for i := range vpath.Path {
+		if logPath.Path[i] != vpath.Path[i] {
+			t.Error(
+				"State", i, "of logarithmic posterior path has to be", vpath.Path[i], "but got", logPath.Path[i],
+			)
+		}
+	}
+	if math.Abs(logPath.Probability-math.Log(vpath.Probability)) > 1e-12 {
+		t.Error(
+			"Logarithmic probability has to be", math.Log(vpath.Probability), "but got", logPath.Probability,
+		)
+	}
+}
+
+func TestViterbiPosteriorAt(t *testing.T) {
+	v, incStates, incomingObservations := healthModel()
+	gamma, err := v.posterior(false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for step := range incomingObservations {
+		marginals, err := v.PosteriorAt(step)
+		if err != nil {
+			t.Fatal(err)
+		}
+		logMarginals, err := logModel(v).PosteriorAtLogProbabilities(step)
+		if err != nil {
+			t.Fatal(err)
+		}
+		sum := 0.0
+		for _, st := range incStates {
+			if math.Abs(marginals[st]-gamma[step][st]) > 1e-12 {
+				t.Error(
+					"Posterior of", st, "at", step, "has to be", gamma[step][st], "but got", marginals[st],
+				)
+			}
+			if math.Abs(math.Exp(logMarginals[st])-marginals[st]) > 1e-12 {
+				t.Error(
+					"Logarithmic posterior of", st, "at", step, "has to be", math.Log(marginals[st]), "but got", logMarginals[st],
+				)
+			}
+			sum += marginals[st]
+		}
+		if math.Abs(sum-1) > 1e-12 {
+			t.Error(
+				"Posteriors at", step, "have to sum to 1, but got", sum,
+			)
+		}
+	}
+
+	for _, step := range []int{-1, len(incomingObservations)} {
+		if _, err := v.PosteriorAt(step); !errors.Is(err, ErrInvalidTimestep) {
+			t.Error(
+				"Error has to be ErrInvalidTimestep, but got", err,
+			)
+		}
+	}
+}
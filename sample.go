@@ -0,0 +1,95 @@
+package viterbi
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"math/rand"
+)
+
+var (
+	// ErrInvalidSampleLength is returned when requested sample length is not positive
+	ErrInvalidSampleLength = errors.New("sample length has to be positive")
+	// ErrDistributionSum is returned when probabilities of distribution do not sum to 1
+	ErrDistributionSum = errors.New("probabilities of distribution have to sum to 1")
+)
+
+// Sample generates synthetic data from the model: hidden states path is drawn from start and transition distributions
+// and observation is emitted for every state from emission distribution.
+// Only added observations (distinct ones) could be emitted. Every distribution has to sum to 1 (use Normalize if needed)
+// When every probability is in [0;1]
+func (v *Viterbi) Sample(length int, rng *rand.Rand) (states []State, observations []Observation, err error) {
+	if length < 1 {
+		return nil, nil, ErrInvalidSampleLength
+	}
+	if len(v.states) == 0 {
+		return nil, nil, ErrNoStates
+	}
+	symbols := distinctObservations(v.observations)
+	if len(symbols) == 0 {
+		return nil, nil, ErrNoObservations
+	}
+
+	states = make([]State, 0, length)
+	observations = make([]Observation, 0, length)
+	weights := make([]float64, len(v.states))
+	for i, st := range v.states {
+		weights[i], _ = v.GetStartProbability(st)
+	}
+	idx, err := sampleIndex(weights, rng)
+	if err != nil {
+		return nil, nil, fmt.Errorf("start probabilities: %w", err)
+	}
+	emissionWeights := make([]float64, len(symbols))
+	for t := 0; t < length; t++ {
+		if t > 0 {
+			from := v.states[idx]
+			for i, to := range v.states {
+				weights[i] = v.transitionProbabilities[v.transitionKey(from, to)]
+			}
+			idx, err = sampleIndex(weights, rng)
+			if err != nil {
+				return nil, nil, fmt.Errorf("transition probabilities from state %v: %w", from, err)
+			}
+		}
+		st := v.states[idx]
+		for j, obs := range symbols {
+			emissionWeights[j], _ = v.GetEmissionProbability(st, obs)
+		}
+		obsIdx, err := sampleIndex(emissionWeights, rng)
+		if err != nil {
+			return nil, nil, fmt.Errorf("emission probabilities of state %v: %w", st, err)
+		}
+		states = append(states, st)
+		observations = append(observations, symbols[obsIdx])
+	}
+	return states, observations, nil
+}
+
+// sampleIndex draws index from categorical distribution
+func sampleIndex(weights []float64, rng *rand.Rand) (int, error) {
+	sum := 0.0
+	for _, w := range weights {
+		if w < 0 || math.IsNaN(w) {
+			return 0, fmt.Errorf("%w: got %v", ErrInvalidProbability, w)
+		}
+		sum += w
+	}
+	if math.Abs(sum-1) > sumTolerance {
+		return 0, fmt.Errorf("%w: got %v", ErrDistributionSum, sum)
+	}
+	u := rng.Float64() * sum
+	last := 0
+	for i, w := range weights {
+		if w == 0 {
+			continue
+		}
+		last = i
+		if u < w {
+			return i, nil
+		}
+		u -= w
+	}
+	// Rounding errors could leave small remainder: fall back to the last possible index
+	return last, nil
+}
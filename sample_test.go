@@ -0,0 +1,53 @@
+package viterbi
+
+import (
+	"errors"
+	"math/rand"
+	"testing"
+)
+
+func TestViterbiSample(t *testing.T) {
+	v, _, _ := healthModel()
+	rng := rand.New(rand.NewSource(1))
+	states, observations, err := v.Sample(200, rng)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(states) != 200 || len(observations) != 200 {
+		t.Fatal(
+			"Expected 200 states and observations, but got:", len(states), len(observations),
+		)
+	}
+
+	// Round-trip: decoding of sampled observations has to recover most of sampled states
+	decoder, _, _ := healthModel()
+	decoder.observations = observations
+	logDecoder := logModel(decoder)
+	vpath, err := logDecoder.EvalPathLogProbabilities()
+	if err != nil {
+		t.Fatal(err)
+	}
+	matched := 0
+	for i := range states {
+		if vpath.Path[i] == states[i] {
+			matched++
+		}
+	}
+	if matched < 120 {
+		t.Error(
+			"Expected most of sampled states to be recovered, but got", matched, "of 200",
+		)
+	}
+
+	v.PutStartProbability(CustomState{Name: "Healty", id: 1}, 0.9)
+	if _, _, err := v.Sample(10, rng); !errors.Is(err, ErrDistributionSum) {
+		t.Error(
+			"Error has to be ErrDistributionSum, but got", err,
+		)
+	}
+	if _, _, err := v.Sample(0, rng); err != ErrInvalidSampleLength {
+		t.Error(
+			"Error has to be ErrInvalidSampleLength, but got", err,
+		)
+	}
+}
@@ -0,0 +1,63 @@
+package viterbi
+
+import (
+	"math"
+)
+
+// rescaleThreshold is the sum of classic probabilities of trellis column below which the column is rescaled
+const rescaleThreshold = 1e-100
+
+// rescaleColumn normalizes trellis column of classic probabilities by its sum when the sum falls below rescaleThreshold
+// (so long sequences do not underflow to zero) or exceeds its reciprocal (so products of densities do not overflow, see SetEmissionsAreDensities)
+// and returns logarithm of applied scaling factor.
+// Relative order of probabilities is preserved, so the best path is not affected.
+// Columns within the thresholds are left untouched and zero is returned.
+// Sum is evaluated in order of states, so result does not depend on map iteration order
+func (v *Viterbi) rescaleColumn(column map[State]ViterbiVal) float64 {
+	sum := 0.0
+	for _, st := range v.states {
+		if val, ok := column[st]; ok {
+			sum += val.prob
+		}
+	}
+	if !needsRescale(sum) {
+		return 0
+	}
+	for st, val := range column {
+		val.prob /= sum
+		column[st] = val
+	}
+	return math.Log(sum)
+}
+
+// rescaleDense is the same as rescaleColumn, but for dense trellis column
+func rescaleDense(column denseColumn) float64 {
+	sum := 0.0
+	for i, val := range column.values {
+		if column.set[i] {
+			sum += val.prob
+		}
+	}
+	if !needsRescale(sum) {
+		return 0
+	}
+	for i := range column.values {
+		if column.set[i] {
+			column.values[i].prob /= sum
+		}
+	}
+	return math.Log(sum)
+}
+
+// needsRescale reports whether column with given sum of classic probabilities is out of rescaleThreshold bounds
+func needsRescale(sum float64) bool {
+	return sum != 0 && (sum < rescaleThreshold || sum > 1/rescaleThreshold)
+}
+
+// logProbability returns logarithm of the probability (or the probability itself when it's already logarithmic)
+func logProbability(logSpace bool, prob float64) float64 {
+	if logSpace {
+		return prob
+	}
+	return math.Log(prob)
+}
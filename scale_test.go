@@ -0,0 +1,52 @@
+package viterbi
+
+import (
+	"math"
+	"testing"
+)
+
+func TestViterbiLongSequenceRescaling(t *testing.T) {
+	v, _, incomingObservations := healthModel()
+	for i := 0; i < 3000; i++ {
+		v.AddObservation(incomingObservations[i%len(incomingObservations)])
+	}
+
+	vpath, err := v.EvalPath()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if vpath.Probability != 0 {
+		t.Error(
+			"Probability of long sequence has to underflow to zero, but got", vpath.Probability,
+		)
+	}
+
+	logPath, err := v.ToLog().EvalPathLogProbabilities()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if math.Abs(vpath.LogProbability-logPath.LogProbability) > 1e-6*math.Abs(logPath.LogProbability) {
+		t.Error(
+			"Logarithmic probability has to be", logPath.LogProbability, ", but got", vpath.LogProbability,
+		)
+	}
+	for i := range logPath.Path {
+		if vpath.Path[i].ID() != logPath.Path[i].ID() {
+			t.Error(
+				"State on position", i, "has to be", logPath.Path[i], ", but got", vpath.Path[i],
+			)
+			break
+		}
+	}
+
+	short, _, _ := healthModel()
+	shortPath, err := short.EvalPath()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if shortPath.LogProbability != math.Log(shortPath.Probability) {
+		t.Error(
+			"Logarithmic probability has to be", math.Log(shortPath.Probability), ", but got", shortPath.LogProbability,
+		)
+	}
+}
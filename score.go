@@ -0,0 +1,70 @@
+package viterbi
+
+import (
+	"errors"
+	"fmt"
+)
+
+var (
+	// ErrPathLength is returned when length of the path does not match number of observations
+	ErrPathLength = errors.New("length of the path has to match number of observations")
+	// ErrMissingProbability is returned when probability required for the path has not been set
+	ErrMissingProbability = errors.New("probability has not been set")
+)
+
+// ScorePath evaluates probability of given states path for stored observations:
+// start·emission·transition·emission·...·end (end probability is one for states without it)
+// When every probability is in [0;1]
+func (v *Viterbi) ScorePath(path []State) (float64, error) {
+	return v.scorePath(path, false)
+}
+
+// ScorePathLog is the same as ScorePath, but when every probability is logarithmic (logarithmic probabilities are summed)
+func (v *Viterbi) ScorePathLog(path []State) (float64, error) {
+	return v.scorePath(path, true)
+}
+
+func (v *Viterbi) scorePath(path []State, logSpace bool) (float64, error) {
+	if err := v.validate(); err != nil {
+		return 0, err
+	}
+	if len(path) != len(v.observations) {
+		return 0, fmt.Errorf("%w: got %d states for %d observations", ErrPathLength, len(path), len(v.observations))
+	}
+	prob, ok := v.startProbabilities[path[0].ID()]
+	if !ok {
+		return 0, fmt.Errorf("%w: start probability of state %v", ErrMissingProbability, path[0])
+	}
+	prob, ok = v.clampProbability(logSpace, prob)
+	if !ok {
+		return 0, fmt.Errorf("%w: start probability %v of state %v", ErrInvalidProbability, prob, path[0])
+	}
+	for t := range path {
+		if t > 0 {
+			if !v.isPredecessor(path[t-1], path[t]) {
+				return 0, fmt.Errorf("%w: transition from state %v to state %v is excluded by predecessors", ErrMissingProbability, path[t-1], path[t])
+			}
+			transitionProb, ok, err := v.transitionProbability(path[t-1], path[t], logSpace)
+			if err != nil {
+				return 0, err
+			}
+			if !ok {
+				return 0, fmt.Errorf("%w: transition probability from state %v to state %v", ErrMissingProbability, path[t-1], path[t])
+			}
+			prob = combine(logSpace, prob, transitionProb)
+		}
+		emissionProb, ok, err := v.emissionProbability(path[t], t, v.unknownAt(t), logSpace)
+		if err != nil {
+			return 0, err
+		}
+		if !ok {
+			return 0, fmt.Errorf("%w: emission probability of state %v for observation %v", ErrMissingProbability, path[t], v.observations[t])
+		}
+		prob = combine(logSpace, prob, emissionProb)
+	}
+	endProb, err := v.endProbability(path[len(path)-1], logSpace)
+	if err != nil {
+		return 0, err
+	}
+	return combine(logSpace, prob, endProb), nil
+}
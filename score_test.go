@@ -0,0 +1,85 @@
+package viterbi
+
+import (
+	"errors"
+	"math"
+	"testing"
+)
+
+func TestViterbiScorePath(t *testing.T) {
+	v, incStates, _ := healthModel()
+	best, err := v.EvalPath()
+	if err != nil {
+		t.Fatal(err)
+	}
+	prob, err := v.ScorePath(best.Path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if prob != best.Probability {
+		t.Error(
+			"Probability of the best path has to be", best.Probability, "but got", prob,
+		)
+	}
+
+	path := []State{incStates[1], incStates[1], incStates[1]}
+	// 0.4·0.1 · 0.6·0.3 · 0.6·0.6
+	expected := 0.4 * 0.1 * 0.6 * 0.3 * 0.6 * 0.6
+	prob, err = v.ScorePath(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if math.Abs(prob-expected) > 1e-15 {
+		t.Error(
+			"Probability has to be", expected, "but got", prob,
+		)
+	}
+	logProb, err := logModel(v).ScorePathLog(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if math.Abs(logProb-math.Log(expected)) > 1e-12 {
+		t.Error(
+			"Logarithmic probability has to be", math.Log(expected), "but got", logProb,
+		)
+	}
+
+	if _, err := v.ScorePath(path[:2]); !errors.Is(err, ErrPathLength) {
+		t.Error(
+			"Error has to be ErrPathLength, but got", err,
+		)
+	}
+	if _, err := v.ScorePath([]State{incStates[0], CustomState{Name: "Unknown", id: 3}, incStates[0]}); !errors.Is(err, ErrMissingProbability) {
+		t.Error(
+			"Error has to be ErrMissingProbability, but got", err,
+		)
+	}
+}
+
+func TestViterbiScorePathPredecessors(t *testing.T) {
+	v, incStates, _ := healthModel()
+	v.SetPredecessors(incStates[1], []State{incStates[1]})
+	if _, err := v.ScorePath([]State{incStates[0], incStates[1], incStates[1]}); !errors.Is(err, ErrMissingProbability) {
+		t.Error(
+			"Error has to be ErrMissingProbability, but got", err,
+		)
+	}
+}
+
+func TestViterbiScorePathEndProbabilities(t *testing.T) {
+	v, incStates, _ := healthModel()
+	v.PutEndProbability(incStates[1], 0.5)
+	best, err := v.EvalPath()
+	if err != nil {
+		t.Fatal(err)
+	}
+	prob, err := v.ScorePath(best.Path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if math.Abs(prob-best.Probability) > 1e-15 {
+		t.Error(
+			"Probability of the best path has to be", best.Probability, "but got", prob,
+		)
+	}
+}
@@ -0,0 +1,235 @@
+package viterbi
+
+import (
+	"fmt"
+	"math"
+)
+
+// TransitionHash2 is key of second-order transition probability: IDs of the state before previous one, previous and current states
+type TransitionHash2 struct {
+	PrevPrev int
+	Prev     int
+	To       int
+}
+
+// statePair is state of second-order trellis: previous and current states
+type statePair struct {
+	prev State
+	cur  State
+}
+
+// PutTransitionProbability2 puts probability of transition to state cur given two previous states (second-order HMM)
+func (m *Model) PutTransitionProbability2(prevPrev, prev, cur State, val float64) {
+	mustState(prevPrev, "PutTransitionProbability2")
+	mustState(prev, "PutTransitionProbability2")
+	mustState(cur, "PutTransitionProbability2")
+	if m.transitionProbabilities2 == nil {
+		m.transitionProbabilities2 = make(map[TransitionHash2]float64)
+	}
+	trKey := TransitionHash2{prevPrev.ID(), prev.ID(), cur.ID()}
+	prevVal, exists := m.transitionProbabilities2[trKey]
+	m.recordOverwrite(prevVal, exists, val, "transition probability from states %v, %v to state %v", prevPrev, prev, cur)
+	if !exists {
+		m.transitionProbabilities2[trKey] = val
+	}
+}
+
+// EvalPath2 evaluates the most probable path of second-order HMM: transition to the state depends on two previous states.
+// First-order transition probabilities are used between the first and the second observations only,
+// second-order ones (see PutTransitionProbability2) are used for the rest.
+// Trellis is expanded over pairs of states, so evaluation takes O(T·N³) time and O(T·N²) memory for N states
+// When every probability is in [0;1]
+// Equal probabilities are resolved according to the tie-breaker (see SetTieBreaker), in favour of the state with the lowest ID() by default
+func (v *Viterbi) EvalPath2() (ViterbiPath, error) {
+	return v.evalPath2(false)
+}
+
+// EvalPath2LogProbabilities is the same as EvalPath2, but when every probability is logarithmic
+func (v *Viterbi) EvalPath2LogProbabilities() (ViterbiPath, error) {
+	return v.evalPath2(true)
+}
+
+func (v *Viterbi) evalPath2(logSpace bool) (ViterbiPath, error) {
+	if err := v.validate(); err != nil {
+		return ViterbiPath{}, err
+	}
+
+	initial := make(map[State]float64)
+	unknown := v.unknownAt(0)
+	for _, st := range v.states {
+		prob, ok, err := v.initialProbability(st, unknown, logSpace)
+		if err != nil {
+			return ViterbiPath{}, err
+		}
+		if ok {
+			initial[st] = prob
+		}
+	}
+	if len(initial) == 0 {
+		return ViterbiPath{}, v.pathBroken(0)
+	}
+	if len(v.observations) == 1 {
+		// There are no transitions at all: the best state is chosen by start·emission·end, as EvalPath does
+		var best State
+		maxPr := math.Inf(-1)
+		for _, st := range v.states {
+			prob, ok := initial[st]
+			if !ok {
+				continue
+			}
+			endProb, err := v.endProbability(st, logSpace)
+			if err != nil {
+				return ViterbiPath{}, err
+			}
+			prob = combine(logSpace, prob, endProb)
+			if best == nil || v.preferState(prob, st, maxPr, best) {
+				best, maxPr = st, prob
+			}
+		}
+		if impossible(logSpace, maxPr) {
+			return ViterbiPath{}, ErrNoValidPath
+		}
+		return ViterbiPath{Probability: maxPr, LogProbability: logProbability(logSpace, maxPr), Path: []State{best}, StepProbabilities: []float64{initial[best]}}, nil
+	}
+
+	// V[t][(p, s)] is the most probable path ending in states p and s at observations t-1 and t; prev of the value is state at t-2
+	V := make([]map[statePair]ViterbiVal, len(v.observations))
+	V[1] = make(map[statePair]ViterbiVal)
+	unknown = v.unknownAt(1)
+	for _, s := range v.states {
+		emissionProb, ok, err := v.emissionProbability(s, 1, unknown, logSpace)
+		if err != nil {
+			return ViterbiPath{}, err
+		}
+		if !ok {
+			continue
+		}
+		for _, p := range v.states {
+			prevProb, ok := initial[p]
+			if !ok {
+				continue
+			}
+			transitionProb, ok, err := v.transitionProbability(p, s, logSpace)
+			if err != nil {
+				return ViterbiPath{}, err
+			}
+			if !ok {
+				continue
+			}
+			prob := combine(logSpace, combine(logSpace, prevProb, transitionProb), emissionProb)
+			if impossible(logSpace, prob) {
+				continue
+			}
+			V[1][statePair{p, s}] = ViterbiVal{prob: prob}
+		}
+	}
+	if len(V[1]) == 0 {
+		return ViterbiPath{}, v.pathBroken(1)
+	}
+
+	for t := 2; t < len(v.observations); t++ {
+		V[t] = make(map[statePair]ViterbiVal)
+		unknown := v.unknownAt(t)
+		for _, s := range v.states {
+			emissionProb, ok, err := v.emissionProbability(s, t, unknown, logSpace)
+			if err != nil {
+				return ViterbiPath{}, err
+			}
+			if !ok {
+				continue
+			}
+			for _, p := range v.states {
+				best := ViterbiVal{}
+				for _, q := range v.states {
+					prevVal, ok := V[t-1][statePair{q, p}]
+					if !ok {
+						continue
+					}
+					transitionProb, ok, err := v.transitionProbability2(q, p, s, logSpace)
+					if err != nil {
+						return ViterbiPath{}, err
+					}
+					if !ok {
+						continue
+					}
+					prob := combine(logSpace, prevVal.prob, transitionProb)
+					if best.prev == nil || v.preferState(prob, q, best.prob, best.prev) {
+						best = ViterbiVal{prob: prob, prev: q}
+					}
+				}
+				if best.prev == nil {
+					continue
+				}
+				prob := combine(logSpace, best.prob, emissionProb)
+				if impossible(logSpace, prob) {
+					continue
+				}
+				V[t][statePair{p, s}] = ViterbiVal{prob: prob, prev: best.prev}
+			}
+		}
+		if len(V[t]) == 0 {
+			return ViterbiPath{}, v.pathBroken(t)
+		}
+	}
+
+	last := len(V) - 1
+	var (
+		bestPair statePair
+		maxPr    = math.Inf(-1)
+	)
+	for pair, val := range V[last] {
+		endProb, err := v.endProbability(pair.cur, logSpace)
+		if err != nil {
+			return ViterbiPath{}, err
+		}
+		prob := combine(logSpace, val.prob, endProb)
+		if bestPair.cur == nil {
+			bestPair, maxPr = pair, prob
+			continue
+		}
+		// Pairs of equal probability are compared by current state, then by previous one
+		a, b := pair.cur, bestPair.cur
+		if a.ID() == b.ID() {
+			a, b = pair.prev, bestPair.prev
+		}
+		if v.preferState(prob, a, maxPr, b) {
+			bestPair, maxPr = pair, prob
+		}
+	}
+	if impossible(logSpace, maxPr) {
+		return ViterbiPath{}, ErrNoValidPath
+	}
+
+	path := make([]State, len(V))
+	path[last], path[last-1] = bestPair.cur, bestPair.prev
+	for t := last; t >= 2; t-- {
+		path[t-2] = V[t][statePair{path[t-1], path[t]}].prev
+	}
+
+	steps := make([]float64, len(path))
+	steps[0] = initial[path[0]]
+	for t := 1; t < len(path); t++ {
+		var transitionProb float64
+		if t == 1 {
+			transitionProb, _, _ = v.transitionProbability(path[0], path[1], logSpace)
+		} else {
+			transitionProb, _, _ = v.transitionProbability2(path[t-2], path[t-1], path[t], logSpace)
+		}
+		emissionProb, _, _ := v.emissionProbability(path[t], t, v.unknownAt(t), logSpace)
+		steps[t] = combine(logSpace, transitionProb, emissionProb)
+	}
+	return ViterbiPath{Probability: maxPr, LogProbability: logProbability(logSpace, maxPr), Path: path, StepProbabilities: steps}, nil
+}
+
+// transitionProbability2 returns probability of second-order transition
+func (v *Viterbi) transitionProbability2(prevPrev, prev, to State, logSpace bool) (float64, bool, error) {
+	transitionProb, ok := v.transitionProbabilities2[TransitionHash2{prevPrev.ID(), prev.ID(), to.ID()}]
+	if !ok {
+		return 0, false, nil
+	}
+	transitionProb, ok = v.clampProbability(logSpace, transitionProb)
+	if !ok {
+		return 0, false, fmt.Errorf("%w: transition probability %v from states %v, %v to state %v", ErrInvalidProbability, transitionProb, prevPrev, prev, to)
+	}
+	return transitionProb, true, nil
+}
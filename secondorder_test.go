@@ -0,0 +1,85 @@
+package viterbi
+
+import (
+	"errors"
+	"math"
+	"testing"
+)
+
+func TestViterbiEvalPath2(t *testing.T) {
+	var (
+		a   = CustomState{Name: "A", id: 1}
+		b   = CustomState{Name: "B", id: 2}
+		obs = CustomObservation{Name: "o", id: 1}
+	)
+	v := New()
+	v.AddState(a)
+	v.AddState(b)
+	for i := 0; i < 4; i++ {
+		v.AddObservation(obs)
+	}
+	v.PutStartProbability(a, 1.0)
+	v.PutEmissionProbability(a, obs, 0.5)
+	v.PutEmissionProbability(b, obs, 0.5)
+	v.PutTransitionProbability(a, a, 0.9)
+	v.PutTransitionProbability(a, b, 0.1)
+	// Two A in a row are always followed by B, everything else is followed by A
+	v.PutTransitionProbability2(a, a, b, 1.0)
+	v.PutTransitionProbability2(a, b, a, 1.0)
+	v.PutTransitionProbability2(b, a, a, 1.0)
+	v.PutTransitionProbability2(b, b, a, 1.0)
+
+	vpath, err := v.EvalPath2()
+	if err != nil {
+		t.Fatal(err)
+	}
+	correctPath := []State{a, a, b, a}
+	for i := range correctPath {
+		if vpath.Path[i].ID() != correctPath[i].ID() {
+			t.Error(
+				"State on position", i, "has to be", correctPath[i], ", but got", vpath.Path[i],
+			)
+		}
+	}
+	// 1·0.5 · 0.9·0.5 · 1·0.5 · 1·0.5
+	if math.Abs(vpath.Probability-0.05625) > 1e-12 {
+		t.Error(
+			"Probability has to be 0.05625, but got", vpath.Probability,
+		)
+	}
+
+	logPath, err := v.ToLog().EvalPath2LogProbabilities()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if math.Abs(math.Exp(logPath.Probability)-vpath.Probability) > 1e-12 {
+		t.Error(
+			"Probability has to be", vpath.Probability, ", but got", math.Exp(logPath.Probability),
+		)
+	}
+
+	// Without B there is no second-order transition after two A in a row
+	v.RemoveState(b)
+	_, err = v.EvalPath2()
+	var brokenErr *PathBrokenError
+	if !errors.As(err, &brokenErr) || brokenErr.ObservationIndex != 2 {
+		t.Error(
+			"Error has to be ErrPathBroken at observation 2, but got", err,
+		)
+	}
+
+	single := New()
+	single.AddState(a)
+	single.AddObservation(obs)
+	single.PutStartProbability(a, 1.0)
+	single.PutEmissionProbability(a, obs, 0.5)
+	singlePath, err := single.EvalPath2()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(singlePath.Path) != 1 || singlePath.Probability != 0.5 {
+		t.Error(
+			"Path has to be [A] with probability 0.5, but got", singlePath,
+		)
+	}
+}
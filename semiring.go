@@ -0,0 +1,206 @@
+package viterbi
+
+import (
+	"math"
+)
+
+// Semiring defines operations of trellis evaluation: EvalPath is max-times, EvalPathLogProbabilities is max-plus,
+// Forward is sum-times and so on. Every stored value (start, emission, transition, end) is passed to operations as is
+type Semiring interface {
+	// Combine extends path by value (⊗): e.g. product of probabilities
+	Combine(a, b float64) float64
+	// Aggregate merges values of alternative paths (⊕): e.g. sum or maximum of probabilities
+	Aggregate(a, b float64) float64
+	// One is identity of Combine, i.e. value of certain event: e.g. 1 for probabilities
+	One() float64
+	// Zero is identity of Aggregate, i.e. value of impossible event: e.g. 0 for probabilities
+	Zero() float64
+	// Power applies weight to the value (see SetEmissionWeight and SetTransitionWeight): e.g. a^w for probabilities
+	Power(a, w float64) float64
+}
+
+// ProbabilitySemiring is sum-product semiring: EvalSemiring with it is the same as Forward
+type ProbabilitySemiring struct{}
+
+// Combine returns a·b
+func (ProbabilitySemiring) Combine(a, b float64) float64 { return a * b }
+
+// Aggregate returns a+b
+func (ProbabilitySemiring) Aggregate(a, b float64) float64 { return a + b }
+
+// One returns 1
+func (ProbabilitySemiring) One() float64 { return 1 }
+
+// Zero returns 0
+func (ProbabilitySemiring) Zero() float64 { return 0 }
+
+// Power returns a^w
+func (ProbabilitySemiring) Power(a, w float64) float64 { return math.Pow(a, w) }
+
+// LogProbabilitySemiring is log-sum-exp/plus semiring: EvalSemiring with it is the same as ForwardLog
+type LogProbabilitySemiring struct{}
+
+// Combine returns a+b
+func (LogProbabilitySemiring) Combine(a, b float64) float64 { return a + b }
+
+// Aggregate returns log(exp(a)+exp(b))
+func (LogProbabilitySemiring) Aggregate(a, b float64) float64 { return LogSumExp(a, b) }
+
+// One returns 0
+func (LogProbabilitySemiring) One() float64 { return 0 }
+
+// Zero returns -Inf
+func (LogProbabilitySemiring) Zero() float64 { return math.Inf(-1) }
+
+// Power returns a·w
+func (LogProbabilitySemiring) Power(a, w float64) float64 { return logPower(a, w) }
+
+// MaxProductSemiring is max-times semiring: EvalSemiring with it evaluates probability of the path found by EvalPath
+type MaxProductSemiring struct{}
+
+// Combine returns a·b
+func (MaxProductSemiring) Combine(a, b float64) float64 { return a * b }
+
+// Aggregate returns max(a, b)
+func (MaxProductSemiring) Aggregate(a, b float64) float64 { return math.Max(a, b) }
+
+// One returns 1
+func (MaxProductSemiring) One() float64 { return 1 }
+
+// Zero returns 0
+func (MaxProductSemiring) Zero() float64 { return 0 }
+
+// Power returns a^w
+func (MaxProductSemiring) Power(a, w float64) float64 { return math.Pow(a, w) }
+
+// MaxPlusSemiring is max-plus semiring: EvalSemiring with it evaluates probability of the path found by EvalPathLogProbabilities
+type MaxPlusSemiring struct{}
+
+// Combine returns a+b
+func (MaxPlusSemiring) Combine(a, b float64) float64 { return a + b }
+
+// Aggregate returns max(a, b)
+func (MaxPlusSemiring) Aggregate(a, b float64) float64 { return math.Max(a, b) }
+
+// One returns 0
+func (MaxPlusSemiring) One() float64 { return 0 }
+
+// Zero returns -Inf
+func (MaxPlusSemiring) Zero() float64 { return math.Inf(-1) }
+
+// Power returns a·w
+func (MaxPlusSemiring) Power(a, w float64) float64 { return logPower(a, w) }
+
+// EvalSemiring evaluates trellis with operations of given semiring and returns aggregation of the last column (combined with end probabilities).
+// Missing probabilities are skipped, observations without emission (see PutNoEmissionAt) and forbidden transitions are One() and Zero() of the semiring,
+// weights are applied via Power(). Values are not checked for range, since meaning of them is defined by semiring
+func (v *Viterbi) EvalSemiring(sr Semiring) (float64, error) {
+	if err := v.validate(); err != nil {
+		return 0, err
+	}
+
+	column := make(map[State]float64)
+	unknown := v.unknownAt(0)
+	for _, st := range v.states {
+		startProb, ok := v.startProbabilities[st.ID()]
+		if !ok {
+			continue
+		}
+		emissionProb, ok := v.semiringEmission(sr, st, 0, unknown)
+		if !ok {
+			continue
+		}
+		column[st] = sr.Combine(startProb, emissionProb)
+	}
+	if len(column) == 0 {
+		return 0, v.pathBroken(0)
+	}
+
+	for t := 1; t < len(v.observations); t++ {
+		next := make(map[State]float64)
+		unknown := v.unknownAt(t)
+		for _, s := range v.states {
+			emissionProb, ok := v.semiringEmission(sr, s, t, unknown)
+			if !ok {
+				continue
+			}
+			var (
+				incoming float64
+				reached  bool
+			)
+			for _, r := range v.predecessorsOf(s) {
+				prev, ok := column[r]
+				if !ok {
+					continue
+				}
+				transitionProb, ok, err := v.semiringTransition(sr, r, s)
+				if err != nil {
+					return 0, err
+				}
+				if !ok {
+					continue
+				}
+				val := sr.Combine(prev, transitionProb)
+				if !reached {
+					incoming, reached = val, true
+					continue
+				}
+				incoming = sr.Aggregate(incoming, val)
+			}
+			if !reached {
+				continue
+			}
+			next[s] = sr.Combine(incoming, emissionProb)
+		}
+		if len(next) == 0 {
+			return 0, v.pathBroken(t)
+		}
+		column = next
+	}
+
+	result := sr.Zero()
+	for _, s := range v.states {
+		val, ok := column[s]
+		if !ok {
+			continue
+		}
+		if endProb, ok := v.endProbabilities[s.ID()]; ok {
+			val = sr.Combine(val, endProb)
+		}
+		result = sr.Aggregate(result, val)
+	}
+	return result, nil
+}
+
+// semiringEmission returns emission probability of the state for observation with index t in terms of the semiring
+func (v *Viterbi) semiringEmission(sr Semiring, s State, t int, unknown bool) (float64, bool) {
+	emissionProb, ok, none := v.lookupEmission(s, t, unknown)
+	if none {
+		return sr.One(), true
+	}
+	if !ok {
+		return 0, false
+	}
+	if v.emissionWeight != nil {
+		emissionProb = sr.Power(emissionProb, *v.emissionWeight)
+	}
+	return emissionProb, true
+}
+
+// semiringTransition returns transition probability between two states in terms of the semiring
+func (v *Viterbi) semiringTransition(sr Semiring, from, to State) (float64, bool, error) {
+	transitionProb, ok, forbidden, err := v.lookupTransition(from, to)
+	if err != nil {
+		return 0, false, err
+	}
+	if forbidden {
+		return sr.Zero(), true, nil
+	}
+	if !ok {
+		return 0, false, nil
+	}
+	if v.transitionWeight != nil {
+		transitionProb = sr.Power(transitionProb, *v.transitionWeight)
+	}
+	return transitionProb, true, nil
+}
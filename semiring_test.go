@@ -0,0 +1,129 @@
+package viterbi
+
+import (
+	"math"
+	"testing"
+)
+
+// minProductSemiring evaluates probability of the least probable path
+type minProductSemiring struct{}
+
+func (minProductSemiring) Combine(a, b float64) float64   { return a * b }
+func (minProductSemiring) Aggregate(a, b float64) float64 { return math.Min(a, b) }
+func (minProductSemiring) One() float64                   { return 1 }
+func (minProductSemiring) Zero() float64                  { return math.Inf(1) }
+func (minProductSemiring) Power(a, w float64) float64     { return math.Pow(a, w) }
+
+func TestViterbiEvalSemiring(t *testing.T) {
+	v, _, _ := healthModel()
+	logV := v.ToLog()
+
+	forward, err := v.Forward()
+	if err != nil {
+		t.Fatal(err)
+	}
+	forwardLog, err := logV.ForwardLog()
+	if err != nil {
+		t.Fatal(err)
+	}
+	vpath, err := v.EvalPath()
+	if err != nil {
+		t.Fatal(err)
+	}
+	logPath, err := logV.EvalPathLogProbabilities()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	cases := []struct {
+		name     string
+		model    *Viterbi
+		sr       Semiring
+		expected float64
+	}{
+		{"sum-product", v, ProbabilitySemiring{}, forward},
+		{"log-sum-exp", logV, LogProbabilitySemiring{}, forwardLog},
+		{"max-product", v, MaxProductSemiring{}, vpath.Probability},
+		{"max-plus", logV, MaxPlusSemiring{}, logPath.Probability},
+		// [Fever, Fever, Healty]: 0.4·0.1 · 0.6·0.3 · 0.4·0.1
+		{"min-product", v, minProductSemiring{}, 0.4 * 0.1 * 0.6 * 0.3 * 0.4 * 0.1},
+	}
+	for _, c := range cases {
+		val, err := c.model.EvalSemiring(c.sr)
+		if err != nil {
+			t.Fatal(c.name, err)
+		}
+		if math.Abs(val-c.expected) > 1e-12 {
+			t.Error(
+				"Value of", c.name, "semiring has to be", c.expected, ", but got", val,
+			)
+		}
+	}
+}
+
+func TestViterbiEvalSemiringOptions(t *testing.T) {
+	cases := []struct {
+		name      string
+		configure func(v *Viterbi, incStates []CustomState)
+	}{
+		{"no emission", func(v *Viterbi, incStates []CustomState) {
+			v.PutNoEmissionAt(1)
+		}},
+		{"forbidden transition", func(v *Viterbi, incStates []CustomState) {
+			v.ForbidTransition(incStates[0], incStates[1])
+		}},
+		{"weights", func(v *Viterbi, incStates []CustomState) {
+			v.SetEmissionWeight(2)
+			v.SetTransitionWeight(0.5)
+		}},
+		{"end probabilities", func(v *Viterbi, incStates []CustomState) {
+			v.PutEndProbability(incStates[0], 0.1)
+			v.PutEndProbability(incStates[1], 0.9)
+		}},
+	}
+	for _, c := range cases {
+		v, incStates, _ := healthModel()
+		c.configure(v, incStates)
+		logV := v.ToLog()
+
+		vpath, err := v.EvalPath()
+		if err != nil {
+			t.Fatal(c.name, err)
+		}
+		val, err := v.EvalSemiring(MaxProductSemiring{})
+		if err != nil {
+			t.Fatal(c.name, err)
+		}
+		if math.Abs(val-vpath.Probability) > 1e-12 {
+			t.Error(
+				"Value of max-product semiring with", c.name, "has to be", vpath.Probability, ", but got", val,
+			)
+		}
+		logPath, err := logV.EvalPathLogProbabilities()
+		if err != nil {
+			t.Fatal(c.name, err)
+		}
+		val, err = logV.EvalSemiring(MaxPlusSemiring{})
+		if err != nil {
+			t.Fatal(c.name, err)
+		}
+		if math.Abs(val-logPath.Probability) > 1e-12 {
+			t.Error(
+				"Value of max-plus semiring with", c.name, "has to be", logPath.Probability, ", but got", val,
+			)
+		}
+		forward, err := v.Forward()
+		if err != nil {
+			t.Fatal(c.name, err)
+		}
+		val, err = v.EvalSemiring(ProbabilitySemiring{})
+		if err != nil {
+			t.Fatal(c.name, err)
+		}
+		if math.Abs(val-forward) > 1e-12 {
+			t.Error(
+				"Value of sum-product semiring with", c.name, "has to be", forward, ", but got", val,
+			)
+		}
+	}
+}
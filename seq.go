@@ -0,0 +1,23 @@
+//go:build go1.23
+
+package viterbi
+
+import (
+	"iter"
+)
+
+// EvalPathSeq is the same as EvalPath, but yields states of the best path in order instead of returning them as slice,
+// so path could be consumed by range-over-func pipelines. Second return value is probability of the path
+func (v *Viterbi) EvalPathSeq() (iter.Seq[State], float64, error) {
+	vpath, err := v.EvalPath()
+	if err != nil {
+		return nil, 0, err
+	}
+	return func(yield func(State) bool) {
+		for _, st := range vpath.Path {
+			if !yield(st) {
+				return
+			}
+		}
+	}, vpath.Probability, nil
+}
@@ -0,0 +1,53 @@
+//go:build go1.23
+
+package viterbi
+
+import (
+	"testing"
+)
+
+func TestViterbiEvalPathSeq(t *testing.T) {
+	v, incStates, _ := healthModel()
+	seq, prob, err := v.EvalPathSeq()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if prob != 0.01512 {
+		t.Error(
+			"Probability has to be 0.01512, but got", prob,
+		)
+	}
+	expected := []State{incStates[0], incStates[0], incStates[1]}
+	i := 0
+	for st := range seq {
+		if st != expected[i] {
+			t.Error(
+				"State", i, "has to be", expected[i], "but got", st,
+			)
+		}
+		i++
+	}
+	if i != len(expected) {
+		t.Error(
+			"Expected", len(expected), "states, but got:", i,
+		)
+	}
+
+	// Consumer could stop early
+	i = 0
+	for range seq {
+		i++
+		break
+	}
+	if i != 1 {
+		t.Error(
+			"Iteration has to stop after the first state, but got", i, "states",
+		)
+	}
+
+	if _, _, err := New().EvalPathSeq(); err != ErrNoStates {
+		t.Error(
+			"Error has to be ErrNoStates, but got", err,
+		)
+	}
+}
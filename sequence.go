@@ -0,0 +1,59 @@
+package viterbi
+
+import (
+	"sync"
+)
+
+// EvalSequence is the same as EvalPath, but decodes given observations instead of added ones.
+// Receiver is not changed, so it's safe to call EvalSequence from several goroutines for the same model
+// as long as nobody changes the model at the same time
+// When every probability is in [0;1]
+func (v *Viterbi) EvalSequence(obs []Observation) (ViterbiPath, error) {
+	return v.Model.decoder(obs).evalPath(false)
+}
+
+// EvalSequenceLogProbabilities is the same as EvalSequence, but when every probability is logarithmic
+func (v *Viterbi) EvalSequenceLogProbabilities(obs []Observation) (ViterbiPath, error) {
+	return v.Model.decoder(obs).evalPath(true)
+}
+
+// EvalBatch decodes every sequence (see EvalSequence) by given number of goroutines.
+// Paths and errors are aligned with sequences: error of one sequence does not stop decoding of the others.
+// Number of workers less than 2 falls back to sequential decoding
+// When every probability is in [0;1]
+func (v *Viterbi) EvalBatch(sequences [][]Observation, workers int) ([]ViterbiPath, []error) {
+	return v.evalBatch(sequences, workers, false)
+}
+
+// EvalBatchLogProbabilities is the same as EvalBatch, but when every probability is logarithmic
+func (v *Viterbi) EvalBatchLogProbabilities(sequences [][]Observation, workers int) ([]ViterbiPath, []error) {
+	return v.evalBatch(sequences, workers, true)
+}
+
+func (v *Viterbi) evalBatch(sequences [][]Observation, workers int, logSpace bool) ([]ViterbiPath, []error) {
+	paths := make([]ViterbiPath, len(sequences))
+	errs := make([]error, len(sequences))
+	if workers < 2 {
+		for i := range sequences {
+			paths[i], errs[i] = v.Model.decoder(sequences[i]).evalPath(logSpace)
+		}
+		return paths, errs
+	}
+	jobs := make(chan int)
+	wg := sync.WaitGroup{}
+	for w := 0; w < workers; w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range jobs {
+				paths[i], errs[i] = v.Model.decoder(sequences[i]).evalPath(logSpace)
+			}
+		}()
+	}
+	for i := range sequences {
+		jobs <- i
+	}
+	close(jobs)
+	wg.Wait()
+	return paths, errs
+}
@@ -0,0 +1,109 @@
+package viterbi
+
+import (
+	"fmt"
+	"sync"
+	"testing"
+)
+
+func TestViterbiEvalSequence(t *testing.T) {
+	v, _, incomingObservations := healthModel()
+	correctPath, err := v.EvalPath()
+	if err != nil {
+		t.Fatal(err)
+	}
+	sequences := [][]Observation{
+		{incomingObservations[0], incomingObservations[1], incomingObservations[2]},
+		{incomingObservations[2], incomingObservations[2]},
+		{incomingObservations[0]},
+	}
+	expected := make([]ViterbiPath, len(sequences))
+	for i := range sequences {
+		expected[i], err = v.EvalSequence(sequences[i])
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+	if expected[0].Probability != correctPath.Probability {
+		t.Error(
+			"Probability has to be", correctPath.Probability, ", but got", expected[0].Probability,
+		)
+	}
+
+	var wg sync.WaitGroup
+	results := make([]ViterbiPath, 100)
+	errs := make([]error, len(results))
+	for i := range results {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			results[i], errs[i] = v.EvalSequence(sequences[i%len(sequences)])
+		}(i)
+	}
+	wg.Wait()
+	for i := range results {
+		if errs[i] != nil {
+			t.Fatal(errs[i])
+		}
+		want := expected[i%len(sequences)]
+		if results[i].Probability != want.Probability || len(results[i].Path) != len(want.Path) {
+			t.Error(
+				"Concurrent evaluation has to give", want, ", but got", results[i],
+			)
+		}
+	}
+
+	// Added observations have not to be changed
+	if len(v.observations) != len(incomingObservations) {
+		t.Error(
+			"Number of observations has to be", len(incomingObservations), ", but got", len(v.observations),
+		)
+	}
+
+	if _, err := v.EvalSequenceLogProbabilities(nil); err != ErrNoObservations {
+		t.Error(
+			"Error has to be ErrNoObservations, but got", err,
+		)
+	}
+}
+
+func TestViterbiEvalBatch(t *testing.T) {
+	v, _, incomingObservations := healthModel()
+	sequences := [][]Observation{
+		{incomingObservations[0], incomingObservations[1], incomingObservations[2]},
+		nil,
+		{incomingObservations[2], incomingObservations[2]},
+		{incomingObservations[0]},
+	}
+	for _, workers := range []int{0, 1, 3, 16} {
+		paths, errs := v.EvalBatch(sequences, workers)
+		if len(paths) != len(sequences) || len(errs) != len(sequences) {
+			t.Fatal(
+				"Expected", len(sequences), "results, but got:", len(paths), len(errs),
+			)
+		}
+		for i := range sequences {
+			expected, err := v.EvalSequence(sequences[i])
+			if err != errs[i] {
+				t.Error(
+					"Error of sequence", i, "has to be", err, ", but got", errs[i],
+				)
+			}
+			if paths[i].String() != expected.String() {
+				t.Error(
+					"Path of sequence", i, "has to be", expected, ", but got", paths[i],
+				)
+			}
+		}
+	}
+
+	paths, errs := logModel(v).EvalBatchLogProbabilities(sequences[:1], 2)
+	if errs[0] != nil {
+		t.Fatal(errs[0])
+	}
+	if fmt.Sprint(paths[0].IDs()) != "[1 1 2]" {
+		t.Error(
+			"Path has to be [1 1 2], but got", paths[0].IDs(),
+		)
+	}
+}
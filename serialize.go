@@ -0,0 +1,241 @@
+package viterbi
+
+import (
+	"fmt"
+	"sort"
+)
+
+// serializedModel is representation of the model where states and observations are referenced by ID()
+type serializedModel struct {
+	States       []int                  `json:"states"`
+	Observations []int                  `json:"observations"`
+	Start        []serializedStart      `json:"start"`
+	End          []serializedStart      `json:"end,omitempty"`
+	Emission     []serializedEmission   `json:"emission"`
+	Transition   []serializedTransition `json:"transition"`
+	// Transition2 are second-order transition probabilities
+	Transition2 []serializedTransition2 `json:"transition2,omitempty"`
+	Duration    []serializedDuration    `json:"duration,omitempty"`
+	// Forbidden are transitions forbidden regardless of probabilities (see ForbidTransition)
+	Forbidden []serializedForbidden `json:"forbidden,omitempty"`
+	// Orientation is how transition probabilities are looked up (see SetTransitionOrientation)
+	Orientation TransitionOrientation `json:"orientation,omitempty"`
+}
+
+type serializedStart struct {
+	State       int         `json:"state"`
+	Probability storedFloat `json:"probability"`
+}
+
+type serializedEmission struct {
+	State       int         `json:"state"`
+	Observation int         `json:"observation"`
+	Probability storedFloat `json:"probability"`
+}
+
+type serializedTransition struct {
+	From        int         `json:"from"`
+	To          int         `json:"to"`
+	Probability storedFloat `json:"probability"`
+}
+
+type serializedForbidden struct {
+	From int `json:"from"`
+	To   int `json:"to"`
+}
+
+type serializedTransition2 struct {
+	PrevPrev    int         `json:"prev_prev"`
+	Prev        int         `json:"prev"`
+	To          int         `json:"to"`
+	Probability storedFloat `json:"probability"`
+}
+
+type serializedDuration struct {
+	State       int         `json:"state"`
+	Duration    int         `json:"duration"`
+	Probability storedFloat `json:"probability"`
+}
+
+// toSerialized converts model into ID-based representation. Entries are sorted by IDs, so output is reproducible
+func (v *Viterbi) toSerialized() serializedModel {
+	model := serializedModel{
+		States:       make([]int, 0, len(v.states)),
+		Observations: make([]int, 0, len(v.observations)),
+		Start:        make([]serializedStart, 0, len(v.startProbabilities)),
+		Emission:     make([]serializedEmission, 0, len(v.emissionProbabilities)),
+		Transition:   make([]serializedTransition, 0, len(v.transitionProbabilities)),
+	}
+	for _, st := range v.states {
+		model.States = append(model.States, st.ID())
+	}
+	for _, obs := range v.observations {
+		model.Observations = append(model.Observations, obs.ID())
+	}
+	for st, val := range v.startProbabilities {
+		model.Start = append(model.Start, serializedStart{State: st, Probability: storedFloat(val)})
+	}
+	sort.Slice(model.Start, func(i, j int) bool {
+		return model.Start[i].State < model.Start[j].State
+	})
+	for st, val := range v.endProbabilities {
+		model.End = append(model.End, serializedStart{State: st, Probability: storedFloat(val)})
+	}
+	sort.Slice(model.End, func(i, j int) bool {
+		return model.End[i].State < model.End[j].State
+	})
+	for key, val := range v.emissionProbabilities {
+		model.Emission = append(model.Emission, serializedEmission{State: key.State, Observation: key.observation, Probability: storedFloat(val)})
+	}
+	sort.Slice(model.Emission, func(i, j int) bool {
+		if model.Emission[i].State != model.Emission[j].State {
+			return model.Emission[i].State < model.Emission[j].State
+		}
+		return model.Emission[i].Observation < model.Emission[j].Observation
+	})
+	for key, val := range v.transitionProbabilities {
+		model.Transition = append(model.Transition, serializedTransition{From: key.From, To: key.To, Probability: storedFloat(val)})
+	}
+	sort.Slice(model.Transition, func(i, j int) bool {
+		if model.Transition[i].From != model.Transition[j].From {
+			return model.Transition[i].From < model.Transition[j].From
+		}
+		return model.Transition[i].To < model.Transition[j].To
+	})
+	for key, val := range v.transitionProbabilities2 {
+		model.Transition2 = append(model.Transition2, serializedTransition2{PrevPrev: key.PrevPrev, Prev: key.Prev, To: key.To, Probability: storedFloat(val)})
+	}
+	sort.Slice(model.Transition2, func(i, j int) bool {
+		if model.Transition2[i].PrevPrev != model.Transition2[j].PrevPrev {
+			return model.Transition2[i].PrevPrev < model.Transition2[j].PrevPrev
+		}
+		if model.Transition2[i].Prev != model.Transition2[j].Prev {
+			return model.Transition2[i].Prev < model.Transition2[j].Prev
+		}
+		return model.Transition2[i].To < model.Transition2[j].To
+	})
+	for key, val := range v.durationProbabilities {
+		model.Duration = append(model.Duration, serializedDuration{State: key.State, Duration: key.Duration, Probability: storedFloat(val)})
+	}
+	sort.Slice(model.Duration, func(i, j int) bool {
+		if model.Duration[i].State != model.Duration[j].State {
+			return model.Duration[i].State < model.Duration[j].State
+		}
+		return model.Duration[i].Duration < model.Duration[j].Duration
+	})
+	for key := range v.forbiddenTransitions {
+		model.Forbidden = append(model.Forbidden, serializedForbidden{From: key.From, To: key.To})
+	}
+	sort.Slice(model.Forbidden, func(i, j int) bool {
+		if model.Forbidden[i].From != model.Forbidden[j].From {
+			return model.Forbidden[i].From < model.Forbidden[j].From
+		}
+		return model.Forbidden[i].To < model.Forbidden[j].To
+	})
+	model.Orientation = v.transitionOrientation
+	return model
+}
+
+// fromSerialized restores model from ID-based representation via provided reconstruction functions
+func fromSerialized(model serializedModel, stateByID func(int) State, obsByID func(int) Observation) (*Viterbi, error) {
+	v := New()
+	state := func(id int) (State, error) {
+		st := stateByID(id)
+		if isNil(st) {
+			return nil, fmt.Errorf("%w: can't reconstruct state with ID %d", ErrUnknownState, id)
+		}
+		return st, nil
+	}
+	observation := func(id int) (Observation, error) {
+		obs := obsByID(id)
+		if isNil(obs) {
+			return nil, fmt.Errorf("%w: can't reconstruct observation with ID %d", ErrUnknownObservation, id)
+		}
+		return obs, nil
+	}
+	for _, id := range model.States {
+		st, err := state(id)
+		if err != nil {
+			return nil, err
+		}
+		v.AddState(st)
+	}
+	for _, id := range model.Observations {
+		obs, err := observation(id)
+		if err != nil {
+			return nil, err
+		}
+		v.AddObservation(obs)
+	}
+	for _, entry := range model.Start {
+		st, err := state(entry.State)
+		if err != nil {
+			return nil, err
+		}
+		v.PutStartProbability(st, float64(entry.Probability))
+	}
+	for _, entry := range model.End {
+		st, err := state(entry.State)
+		if err != nil {
+			return nil, err
+		}
+		v.PutEndProbability(st, float64(entry.Probability))
+	}
+	for _, entry := range model.Emission {
+		st, err := state(entry.State)
+		if err != nil {
+			return nil, err
+		}
+		obs, err := observation(entry.Observation)
+		if err != nil {
+			return nil, err
+		}
+		v.PutEmissionProbability(st, obs, float64(entry.Probability))
+	}
+	for _, entry := range model.Transition {
+		from, err := state(entry.From)
+		if err != nil {
+			return nil, err
+		}
+		to, err := state(entry.To)
+		if err != nil {
+			return nil, err
+		}
+		v.PutTransitionProbability(from, to, float64(entry.Probability))
+	}
+	for _, entry := range model.Transition2 {
+		prevPrev, err := state(entry.PrevPrev)
+		if err != nil {
+			return nil, err
+		}
+		prev, err := state(entry.Prev)
+		if err != nil {
+			return nil, err
+		}
+		to, err := state(entry.To)
+		if err != nil {
+			return nil, err
+		}
+		v.PutTransitionProbability2(prevPrev, prev, to, float64(entry.Probability))
+	}
+	for _, entry := range model.Duration {
+		st, err := state(entry.State)
+		if err != nil {
+			return nil, err
+		}
+		v.SetDurationProbability(st, entry.Duration, float64(entry.Probability))
+	}
+	for _, entry := range model.Forbidden {
+		from, err := state(entry.From)
+		if err != nil {
+			return nil, err
+		}
+		to, err := state(entry.To)
+		if err != nil {
+			return nil, err
+		}
+		v.ForbidTransition(from, to)
+	}
+	v.SetTransitionOrientation(model.Orientation)
+	return v, nil
+}
@@ -0,0 +1,114 @@
+package viterbi
+
+// Session decodes snapshot of the model and observations, caching evaluated lattices between calls:
+// EvalPath and EvalPathN of the same session are served from single list trellis (see EvalPathN), which is evaluated again
+// only when more paths are requested than ever before; EvalPosterior evaluates forward and backward variables once.
+// Since the best path is restored from list trellis, pruning and minimum probability (SetBeamWidth, SetPruneRatio, SetMinProbability) are not applied.
+// Changes of the model made after session has been started are not seen by it (so cache never gets stale): start new session for them.
+// Returned paths are not shared with the cache. Session is not safe for concurrent use
+type Session struct {
+	v        *Viterbi
+	logSpace bool
+
+	// lattice is cached list trellis for the largest requested number of paths
+	lattice    *latticeN
+	latticeErr error
+	// best and paths are cached results of EvalPath and EvalPathN (every path list trellis keeps) restored from lattice
+	best  *ViterbiPath
+	paths []ViterbiPath
+	// gamma are cached posterior probabilities of EvalPosterior
+	gamma    []map[State]float64
+	gammaErr error
+}
+
+// NewSession starts session decoding copy of the model and observations (see Clone)
+// When every probability is in [0;1]
+func (v *Viterbi) NewSession() *Session {
+	return &Session{v: v.Clone()}
+}
+
+// NewSessionLogProbabilities is the same as NewSession, but when every probability is logarithmic
+func (v *Viterbi) NewSessionLogProbabilities() *Session {
+	return &Session{v: v.Clone(), logSpace: true}
+}
+
+// EvalPath evaluates the most probable path (see Viterbi.EvalPath). It's restored from cached list trellis (evaluated on the first call when there is none)
+func (s *Session) EvalPath() (ViterbiPath, error) {
+	lattice, err := s.evalLattice(1)
+	if err != nil {
+		return ViterbiPath{}, err
+	}
+	if s.best == nil {
+		path, err := s.v.backtrack(lattice.bestColumns(), s.logSpace, lattice.logScale)
+		if err != nil {
+			return ViterbiPath{}, err
+		}
+		s.best = &path
+	}
+	return copyPath(*s.best), nil
+}
+
+// EvalPathN evaluates up to k most probable distinct paths (see Viterbi.EvalPathN).
+// List trellis is evaluated again only when k exceeds every previously requested number of paths
+func (s *Session) EvalPathN(k int) ([]ViterbiPath, error) {
+	if k < 1 {
+		return nil, ErrInvalidPathsNumber
+	}
+	lattice, err := s.evalLattice(k)
+	if err != nil {
+		return nil, err
+	}
+	if s.paths == nil {
+		if s.paths, err = s.v.pathsN(*lattice, lattice.k, s.logSpace); err != nil {
+			return nil, err
+		}
+	}
+	n := len(s.paths)
+	if n > k {
+		n = k
+	}
+	paths := make([]ViterbiPath, n)
+	for i := range paths {
+		paths[i] = copyPath(s.paths[i])
+	}
+	return paths, nil
+}
+
+// EvalPosterior evaluates sequence of individually most probable states (see Viterbi.EvalPosterior).
+// Forward and backward variables are evaluated on the first call only
+func (s *Session) EvalPosterior() (ViterbiPath, error) {
+	if s.gamma == nil && s.gammaErr == nil {
+		s.gamma, s.gammaErr = s.v.posterior(s.logSpace)
+	}
+	if s.gammaErr != nil {
+		return ViterbiPath{}, s.gammaErr
+	}
+	return s.v.posteriorPath(s.gamma, s.logSpace), nil
+}
+
+// evalLattice returns cached list trellis keeping at least k paths in every cell, evaluating it when there is none.
+// Errors do not depend on number of paths, so failed evaluation is not repeated
+func (s *Session) evalLattice(k int) (*latticeN, error) {
+	if s.latticeErr == nil && (s.lattice == nil || k > s.lattice.k) {
+		lattice, err := s.v.evalLatticeN(k, s.logSpace)
+		if err != nil {
+			s.latticeErr = err
+		} else {
+			// The best path does not depend on number of paths, while the rest have to be restored again
+			s.lattice, s.paths = &lattice, nil
+		}
+	}
+	return s.lattice, s.latticeErr
+}
+
+// copyPath returns path which does not share slices with given one
+func copyPath(p ViterbiPath) ViterbiPath {
+	p.Path = append([]State{}, p.Path...)
+	if p.StepProbabilities != nil {
+		p.StepProbabilities = append([]float64{}, p.StepProbabilities...)
+	}
+	if p.Ambiguous != nil {
+		p.Ambiguous = append([]bool{}, p.Ambiguous...)
+	}
+	return p
+}
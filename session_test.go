@@ -0,0 +1,167 @@
+package viterbi
+
+import (
+	"fmt"
+	"testing"
+)
+
+func TestViterbiSession(t *testing.T) {
+	v, incStates, incomingObservations := healthModel()
+	// Emission of 'dizzy' by 'Fever' is computed, so evaluations could be counted
+	delete(v.emissionProbabilities, EmissionHash{incStates[1].ID(), incomingObservations[2].ID()})
+	calls := 0
+	v.SetEmissionFunc(func(s State, obs Observation) float64 {
+		calls++
+		return 0.6
+	})
+	expected, err := v.EvalPath()
+	if err != nil {
+		t.Fatal(err)
+	}
+	expectedN, err := v.EvalPathN(3)
+	if err != nil {
+		t.Fatal(err)
+	}
+	expectedPosterior, err := v.EvalPosterior()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	session := v.NewSession()
+	// Changes of the model are not seen by started session
+	v.PutStartProbability(incStates[1], 1)
+
+	vpath, err := session.EvalPath()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if vpath.String() != expected.String() {
+		t.Error(
+			"Path has to be", expected, ", but got", vpath,
+		)
+	}
+	calls = 0
+	if _, err := session.EvalPath(); err != nil {
+		t.Fatal(err)
+	}
+	if calls != 0 {
+		t.Error(
+			"Trellis has to be evaluated once, but emission has been computed", calls, "times again",
+		)
+	}
+
+	paths, err := session.EvalPathN(3)
+	if err != nil {
+		t.Fatal(err)
+	}
+	calls = 0
+	paths2, err := session.EvalPathN(2)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if calls != 0 {
+		t.Error(
+			"List trellis has to be evaluated once, but emission has been computed", calls, "times again",
+		)
+	}
+	if len(paths) != len(expectedN) || len(paths2) != 2 {
+		t.Fatal(
+			"Expected", len(expectedN), "and 2 paths, but got:", len(paths), len(paths2),
+		)
+	}
+	for i := range paths {
+		if paths[i].String() != expectedN[i].String() {
+			t.Error(
+				"Path", i, "has to be", expectedN[i], ", but got", paths[i],
+			)
+		}
+	}
+	for i := range paths2 {
+		if paths2[i].String() != expectedN[i].String() {
+			t.Error(
+				"Path", i, "has to be", expectedN[i], ", but got", paths2[i],
+			)
+		}
+	}
+
+	posterior, err := session.EvalPosterior()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if posterior.String() != expectedPosterior.String() {
+		t.Error(
+			"Posterior path has to be", expectedPosterior, ", but got", posterior,
+		)
+	}
+	calls = 0
+	if _, err := session.EvalPosterior(); err != nil {
+		t.Fatal(err)
+	}
+	if calls != 0 {
+		t.Error(
+			"Forward and backward variables have to be evaluated once, but emission has been computed", calls, "times again",
+		)
+	}
+
+	if _, err := session.EvalPathN(0); err != ErrInvalidPathsNumber {
+		t.Error(
+			"Error has to be ErrInvalidPathsNumber, but got", err,
+		)
+	}
+}
+
+func TestViterbiSessionSingleLattice(t *testing.T) {
+	v, _, _ := healthModel()
+	v.SetAmbiguityEpsilon(0.9)
+	expected, err := v.EvalPath()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	session := v.NewSession()
+	if _, err := session.EvalPathN(3); err != nil {
+		t.Fatal(err)
+	}
+	lattice := session.lattice
+	vpath, err := session.EvalPath()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if session.lattice != lattice {
+		t.Error(
+			"The best path has to be restored from cached list trellis",
+		)
+	}
+	if vpath.String() != expected.String() || fmt.Sprint(vpath.Ambiguous) != fmt.Sprint(expected.Ambiguous) {
+		t.Error(
+			"Path has to be", expected, expected.Ambiguous, ", but got", vpath, vpath.Ambiguous,
+		)
+	}
+
+	// Returned paths are not shared with the cache
+	vpath.Path[0] = nil
+	vpath.Ambiguous[0] = !vpath.Ambiguous[0]
+	paths, err := session.EvalPathN(1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	paths[0].Path[0] = nil
+	vpath, err = session.EvalPath()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if vpath.String() != expected.String() || vpath.Ambiguous[0] != expected.Ambiguous[0] {
+		t.Error(
+			"Cached path has to stay", expected, ", but got", vpath,
+		)
+	}
+	paths, err = session.EvalPathN(1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if paths[0].String() != expected.String() {
+		t.Error(
+			"Cached path has to stay", expected, ", but got", paths[0],
+		)
+	}
+}
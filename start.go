@@ -0,0 +1,37 @@
+package viterbi
+
+// EvalPathWithStart is the same as EvalPath, but start probabilities of the model are replaced by given ones for this evaluation only.
+// Model itself is not changed, so the same model could be evaluated with different start distributions
+// When every probability is in [0;1]
+func (v *Viterbi) EvalPathWithStart(start map[State]float64) (ViterbiPath, error) {
+	return v.withStart(start).evalPath(false)
+}
+
+// EvalPathWithStartLogProbabilities is the same as EvalPathWithStart, but when every probability is logarithmic
+func (v *Viterbi) EvalPathWithStartLogProbabilities(start map[State]float64) (ViterbiPath, error) {
+	return v.withStart(start).evalPath(true)
+}
+
+// withStart returns shallow copy of the model with given start probabilities
+func (v *Viterbi) withStart(start map[State]float64) *Viterbi {
+	copied := *v
+	copied.startProbabilities = make(map[int]float64, len(start))
+	for st, prob := range start {
+		copied.startProbabilities[st.ID()] = prob
+	}
+	return &copied
+}
+
+// EvalPathWithInitial evaluates the most probable path with trellis of the first observation seeded by given prior distribution
+// (times emission of the first observation) instead of start probabilities of the model. It's meant for warm start of sliding-window decoding:
+// prior is posterior of the overlapping observation of the previous window (see PosteriorAt), so windows are stitched without hard reset
+// at every boundary. States missing in prior are impossible at the first observation; model itself is not changed
+// When every probability is in [0;1]
+func (v *Viterbi) EvalPathWithInitial(initial map[State]float64) (ViterbiPath, error) {
+	return v.withStart(initial).evalPath(false)
+}
+
+// EvalPathWithInitialLogProbabilities is the same as EvalPathWithInitial, but when every probability (prior included) is logarithmic
+func (v *Viterbi) EvalPathWithInitialLogProbabilities(initial map[State]float64) (ViterbiPath, error) {
+	return v.withStart(initial).evalPath(true)
+}
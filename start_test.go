@@ -0,0 +1,98 @@
+package viterbi
+
+import (
+	"fmt"
+	"math"
+	"testing"
+)
+
+func TestViterbiEvalPathWithStart(t *testing.T) {
+	v, incStates, _ := healthModel()
+	correctPath, err := v.EvalPath()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Every path has to start in 'Fever'
+	vpath, err := v.EvalPathWithStart(map[State]float64{incStates[1]: 1.0})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if vpath.Path[0].ID() != incStates[1].ID() {
+		t.Error(
+			"Path has to start in", incStates[1], ", but got", vpath.Path[0],
+		)
+	}
+
+	// Stored start probabilities have not to be changed
+	again, err := v.EvalPath()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if again.Probability != correctPath.Probability {
+		t.Error(
+			"Probability has to be", correctPath.Probability, ", but got", again.Probability,
+		)
+	}
+	if val, _ := v.GetStartProbability(incStates[0]); val != 0.6 {
+		t.Error(
+			"Start probability of 'Healty' has to be 0.6, but got", val,
+		)
+	}
+
+	logPath, err := v.ToLog().EvalPathWithStartLogProbabilities(map[State]float64{incStates[1]: 0})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if logPath.Path[0].ID() != incStates[1].ID() {
+		t.Error(
+			"Path has to start in", incStates[1], ", but got", logPath.Path[0],
+		)
+	}
+
+	if _, err := v.EvalPathWithStart(map[State]float64{}); err == nil {
+		t.Error(
+			"Evaluation without start probabilities has to fail",
+		)
+	}
+}
+
+func TestViterbiEvalPathWithInitial(t *testing.T) {
+	v, _, incomingObservations := healthModel()
+
+	// The first window ends at 'cold': its posterior seeds the second window
+	v.observations = []Observation{incomingObservations[0], incomingObservations[1]}
+	prior, err := v.PosteriorAt(1)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	v.observations = []Observation{incomingObservations[1], incomingObservations[2]}
+	vpath, err := v.EvalPathWithInitial(prior)
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected, err := v.EvalPathWithStart(prior)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if vpath.String() != expected.String() || fmt.Sprint(vpath.IDs()) != "[1 2]" {
+		t.Error(
+			"Path has to be", expected, ", but got", vpath,
+		)
+	}
+
+	logPrior := make(map[State]float64, len(prior))
+	for st, prob := range prior {
+		logPrior[st] = math.Log(prob)
+	}
+	logPath, err := logModel(v).EvalPathWithInitialLogProbabilities(logPrior)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if fmt.Sprint(logPath.IDs()) != "[1 2]" || math.Abs(logPath.Probability-math.Log(vpath.Probability)) > 1e-9 {
+		t.Error(
+			"Logarithmic path has to be", math.Log(vpath.Probability), vpath.IDs(), ", but got", logPath.Probability, logPath.IDs(),
+		)
+	}
+}
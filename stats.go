@@ -0,0 +1,68 @@
+package viterbi
+
+import (
+	"context"
+)
+
+// DecodeStats is statistics of single evaluation
+type DecodeStats struct {
+	// ActiveStates is number of states kept in trellis column of every observation
+	ActiveStates []int
+	// Pruned is total number of states removed by beam search (see SetBeamWidth and SetPruneRatio)
+	Pruned int
+	// PrunedCells are removed states together with observations they have been removed for, in order of evaluation.
+	// Exact and pruned decodes could diverge only at these cells
+	PrunedCells []PrunedCell
+	// PeakWidth is maximum number of states kept in single trellis column
+	PeakWidth int
+}
+
+// PrunedCell is trellis cell removed by beam search
+type PrunedCell struct {
+	// ObservationIndex is position of the observation in the sequence
+	ObservationIndex int
+	State            State
+}
+
+// EvalPathStats is the same as EvalPath, but returns statistics of evaluation alongside the best path
+// When every probability is in [0;1]
+func (v *Viterbi) EvalPathStats() (ViterbiPath, DecodeStats, error) {
+	return v.evalPathStats(false)
+}
+
+// EvalPathStatsLogProbabilities is the same as EvalPathStats, but when every probability is logarithmic
+func (v *Viterbi) EvalPathStatsLogProbabilities() (ViterbiPath, DecodeStats, error) {
+	return v.evalPathStats(true)
+}
+
+func (v *Viterbi) evalPathStats(logSpace bool) (ViterbiPath, DecodeStats, error) {
+	stats := DecodeStats{ActiveStates: make([]int, 0, len(v.observations))}
+	V, logScale, err := v.evalTrellis(context.Background(), evalOptions{logSpace: logSpace, stats: &stats})
+	if err != nil {
+		return ViterbiPath{}, stats, err
+	}
+	path, err := v.backtrack(V, logSpace, logScale)
+	return path, stats, err
+}
+
+// addColumn accounts trellis column kept for the next observation
+func (stats *DecodeStats) addColumn(column map[State]ViterbiVal) {
+	if stats == nil {
+		return
+	}
+	stats.ActiveStates = append(stats.ActiveStates, len(column))
+	if len(column) > stats.PeakWidth {
+		stats.PeakWidth = len(column)
+	}
+}
+
+// addPruned accounts states removed by pruning for observation t
+func (stats *DecodeStats) addPruned(t int, pruned []State) {
+	if stats == nil {
+		return
+	}
+	stats.Pruned += len(pruned)
+	for _, st := range pruned {
+		stats.PrunedCells = append(stats.PrunedCells, PrunedCell{ObservationIndex: t, State: st})
+	}
+}
@@ -0,0 +1,64 @@
+package viterbi
+
+import (
+	"testing"
+)
+
+func TestViterbiEvalPathStats(t *testing.T) {
+	v, _, incomingObservations := healthModel()
+	vpath, stats, err := v.EvalPathStats()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if vpath.Probability != 0.01512 {
+		t.Error(
+			"Probability has to be 0.01512, but got", vpath.Probability,
+		)
+	}
+	if len(stats.ActiveStates) != len(incomingObservations) {
+		t.Fatal(
+			"Number of columns has to be", len(incomingObservations), ", but got", len(stats.ActiveStates),
+		)
+	}
+	for i, active := range stats.ActiveStates {
+		if active != 2 {
+			t.Error(
+				"Number of active states at", i, "has to be 2, but got", active,
+			)
+		}
+	}
+	if stats.Pruned != 0 || stats.PeakWidth != 2 {
+		t.Error(
+			"Expected no pruned states and peak width of 2, but got", stats,
+		)
+	}
+
+	v.SetBeamWidth(1)
+	_, stats, err = v.EvalPathStatsLogProbabilities()
+	if err != nil {
+		t.Fatal(err)
+	}
+	// One of two states is pruned at every observation
+	if stats.Pruned != len(incomingObservations) || stats.PeakWidth != 1 {
+		t.Error(
+			"Expected", len(incomingObservations), "pruned states and peak width of 1, but got", stats,
+		)
+	}
+
+	_, stats, err = v.EvalPathStats()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(stats.PrunedCells) != stats.Pruned {
+		t.Fatal(
+			"Expected", stats.Pruned, "pruned cells, but got", stats.PrunedCells,
+		)
+	}
+	for i, expected := range []int{2, 2, 1} {
+		if cell := stats.PrunedCells[i]; cell.ObservationIndex != i || cell.State.ID() != expected {
+			t.Error(
+				"State", expected, "has to be pruned at observation", i, "but got", cell,
+			)
+		}
+	}
+}
@@ -0,0 +1,18 @@
+package viterbi
+
+// stepProbabilities evaluates local probability of the path at every observation:
+// start·emission for the first observation and transition·emission for the rest.
+// Path is expected to be evaluated already, so probabilities are known to be valid
+func (v *Viterbi) stepProbabilities(path []State, logSpace bool) []float64 {
+	steps := make([]float64, len(path))
+	for t := range path {
+		if t == 0 {
+			steps[t], _, _ = v.initialProbability(path[t], v.unknownAt(0), logSpace)
+			continue
+		}
+		transitionProb, _, _ := v.transitionProbability(path[t-1], path[t], logSpace)
+		emissionProb, _, _ := v.emissionProbability(path[t], t, v.unknownAt(t), logSpace)
+		steps[t] = combine(logSpace, transitionProb, emissionProb)
+	}
+	return steps
+}
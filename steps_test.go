@@ -0,0 +1,59 @@
+package viterbi
+
+import (
+	"math"
+	"testing"
+)
+
+func TestViterbiStepProbabilities(t *testing.T) {
+	v, _, _ := healthModel()
+	vpath, err := v.EvalPath()
+	if err != nil {
+		t.Fatal(err)
+	}
+	// [Healty, Healty, Fever]: 0.6·0.5, 0.7·0.4, 0.3·0.6
+	correctSteps := []float64{0.3, 0.28, 0.18}
+	if len(vpath.StepProbabilities) != len(correctSteps) {
+		t.Fatal(
+			"Number of step probabilities has to be", len(correctSteps), ", but got", len(vpath.StepProbabilities),
+		)
+	}
+	product := 1.0
+	for i := range correctSteps {
+		if math.Abs(vpath.StepProbabilities[i]-correctSteps[i]) > 1e-12 {
+			t.Error(
+				"Step probability on position", i, "has to be", correctSteps[i], ", but got", vpath.StepProbabilities[i],
+			)
+		}
+		product *= vpath.StepProbabilities[i]
+	}
+	if math.Abs(product-vpath.Probability) > 1e-12 {
+		t.Error(
+			"Product of step probabilities has to be", vpath.Probability, ", but got", product,
+		)
+	}
+
+	paths, err := v.EvalPathN(2)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, path := range paths {
+		if len(path.StepProbabilities) != len(path.Path) {
+			t.Error(
+				"Every k-best path has to have step probabilities, but got", path.StepProbabilities,
+			)
+		}
+	}
+
+	logPath, err := v.ToLog().EvalPathLogProbabilitiesCompact()
+	if err != nil {
+		t.Fatal(err)
+	}
+	for i := range correctSteps {
+		if math.Abs(math.Exp(logPath.StepProbabilities[i])-correctSteps[i]) > 1e-12 {
+			t.Error(
+				"Step probability on position", i, "has to be", correctSteps[i], ", but got", math.Exp(logPath.StepProbabilities[i]),
+			)
+		}
+	}
+}
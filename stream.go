@@ -0,0 +1,81 @@
+package viterbi
+
+import (
+	"errors"
+)
+
+// ErrStreamNotStarted is returned when online evaluation is used before Begin
+var ErrStreamNotStarted = errors.New("stream has not been started: call Begin first")
+
+// streamState is trellis of online evaluation
+type streamState struct {
+	logSpace bool
+	trellis  []map[State]ViterbiVal
+	logScale float64
+}
+
+// Begin starts online (streaming) evaluation: observations are provided one by one via Step and
+// the best path could be requested at any point via CurrentBest.
+// Start, emission and transition probabilities have to be configured up front.
+// Previously added observations are discarded
+// When every probability is in [0;1]
+func (v *Viterbi) Begin() error {
+	return v.begin(false)
+}
+
+// BeginLogProbabilities is the same as Begin, but when every probability is logarithmic
+func (v *Viterbi) BeginLogProbabilities() error {
+	return v.begin(true)
+}
+
+func (v *Viterbi) begin(logSpace bool) error {
+	if len(v.states) == 0 {
+		return ErrNoStates
+	}
+	v.observations = v.observations[:0]
+	v.stream = &streamState{logSpace: logSpace}
+	return nil
+}
+
+// Step adds observation to the stream and evaluates single trellis column for it.
+// If observation breaks the path then ErrPathBroken is returned and stream stays untouched
+func (v *Viterbi) Step(obs Observation) error {
+	if v.stream == nil {
+		return ErrStreamNotStarted
+	}
+	v.observations = append(v.observations, obs)
+	t := len(v.observations) - 1
+	column, err := v.streamColumn(t)
+	if err == nil && len(column) == 0 {
+		err = v.pathBroken(t)
+	}
+	if err != nil {
+		v.observations = v.observations[:t]
+		return err
+	}
+	if !v.stream.logSpace {
+		v.stream.logScale += v.rescaleColumn(column)
+	}
+	v.stream.trellis = append(v.stream.trellis, column)
+	return nil
+}
+
+// streamColumn evaluates trellis column for observation t of the stream
+func (v *Viterbi) streamColumn(t int) (map[State]ViterbiVal, error) {
+	opts := evalOptions{logSpace: v.stream.logSpace}
+	if t > 0 {
+		return v.evalColumn(v.stream.trellis[t-1], t, opts)
+	}
+	return v.evalInitialColumn(opts)
+}
+
+// CurrentBest returns the most probable path for observations streamed so far
+func (v *Viterbi) CurrentBest() (ViterbiPath, error) {
+	if v.stream == nil {
+		return ViterbiPath{}, ErrStreamNotStarted
+	}
+	if len(v.stream.trellis) == 0 {
+		return ViterbiPath{}, ErrNoObservations
+	}
+	return v.backtrack(v.stream.trellis, v.stream.logSpace, v.stream.logScale)
+}
@@ -0,0 +1,73 @@
+package viterbi
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestViterbiStream(t *testing.T) {
+	v, incStates, incomingObservations := healthModel()
+	if err := v.Step(incomingObservations[0]); err != ErrStreamNotStarted {
+		t.Error(
+			"Error has to be ErrStreamNotStarted, but got", err,
+		)
+	}
+	if err := v.Begin(); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := v.CurrentBest(); err != ErrNoObservations {
+		t.Error(
+			"Error has to be ErrNoObservations, but got", err,
+		)
+	}
+
+	// Best paths of the classic example after every observation
+	expected := []ViterbiPath{
+		{Probability: 0.3, Path: []State{incStates[0]}},
+		{Probability: 0.084, Path: []State{incStates[0], incStates[0]}},
+		{Probability: 0.01512, Path: []State{incStates[0], incStates[0], incStates[1]}},
+	}
+	for i := range incomingObservations {
+		if err := v.Step(incomingObservations[i]); err != nil {
+			t.Fatal(err)
+		}
+		vpath, err := v.CurrentBest()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if diff := vpath.Probability - expected[i].Probability; diff > 1e-12 || diff < -1e-12 {
+			t.Error(
+				"Probability after", i+1, "observations has to be", expected[i].Probability, "but got", vpath.Probability,
+			)
+		}
+		if len(vpath.Path) != len(expected[i].Path) {
+			t.Error(
+				"Expected", len(expected[i].Path), "states, but got:", len(vpath.Path),
+			)
+			continue
+		}
+		for j := range expected[i].Path {
+			if vpath.Path[j] != expected[i].Path[j] {
+				t.Error(
+					"State", j, "after", i+1, "observations has to be", expected[i].Path[j], "but got", vpath.Path[j],
+				)
+			}
+		}
+	}
+
+	// Observation without emissions breaks the path, but stream stays untouched
+	if err := v.Step(CustomObservation{Name: "unknown", id: 4}); !errors.Is(err, ErrPathBroken) {
+		t.Error(
+			"Error has to be ErrPathBroken, but got", err,
+		)
+	}
+	vpath, err := v.CurrentBest()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(vpath.Path) != 3 || vpath.Probability != 0.01512 {
+		t.Error(
+			"Stream has to stay untouched after broken step, but got", vpath,
+		)
+	}
+}
@@ -0,0 +1,261 @@
+package viterbi
+
+import (
+	"errors"
+	"fmt"
+	"math"
+)
+
+// ErrNoSequences is returned when training is called without sequences
+var ErrNoSequences = errors.New("no training sequences have been provided")
+
+// Train estimates start, emission and transition probabilities from unlabeled observation sequences
+// via Baum-Welch (EM) algorithm https://en.wikipedia.org/wiki/Baum%E2%80%93Welch_algorithm
+// Iterations stop when change of log-likelihood is less than tol or maxIter is reached.
+// Model has to be initialized: only stored probabilities are re-estimated (in place), so zero structure of the model is kept.
+// Default probabilities (if set) are not re-estimated
+// When every probability is in [0;1]
+func (v *Viterbi) Train(sequences [][]Observation, maxIter int, tol float64) error {
+	_, err := v.TrainWithLikelihoods(sequences, maxIter, tol)
+	return err
+}
+
+// TrainWithLikelihoods is the same as Train, but returns total log-likelihood of sequences for every iteration
+// (evaluated for parameters before re-estimation), so convergence could be checked
+func (v *Viterbi) TrainWithLikelihoods(sequences [][]Observation, maxIter int, tol float64) ([]float64, error) {
+	if len(sequences) == 0 {
+		return nil, ErrNoSequences
+	}
+	if len(v.states) == 0 {
+		return nil, ErrNoStates
+	}
+	likelihoods := []float64{}
+	for iter := 0; iter < maxIter; iter++ {
+		likelihood, err := v.baumWelchStep(sequences)
+		if err != nil {
+			return likelihoods, err
+		}
+		likelihoods = append(likelihoods, likelihood)
+		if iter > 0 && math.Abs(likelihood-likelihoods[iter-1]) < tol {
+			break
+		}
+	}
+	return likelihoods, nil
+}
+
+// baumWelchStep evaluates expected counts over every sequence and re-estimates probabilities.
+// It returns total log-likelihood of sequences for parameters before re-estimation
+func (v *Viterbi) baumWelchStep(sequences [][]Observation) (float64, error) {
+	logV := v.withProbabilities(math.Log)
+	var (
+		startCounts      = make(map[int]float64)
+		emissionCounts   = make(map[EmissionHash]float64)
+		emissionTotals   = make(map[int]float64)
+		transitionCounts = make(map[TransitionHash]float64)
+		transitionTotals = make(map[int]float64)
+		total            = 0.0
+	)
+	for i, seq := range sequences {
+		seqModel := *logV
+		seqModel.observations = seq
+		// Constraints, time-indexed emissions and missing marks belong to observations of the model, not to training sequences
+		seqModel.constraints, seqModel.timedEmissions, seqModel.noEmissions = nil, nil, nil
+		alpha, err := seqModel.forward(true)
+		if err != nil {
+			return 0, fmt.Errorf("sequence %d: %w", i, err)
+		}
+		beta, err := seqModel.backward(true)
+		if err != nil {
+			return 0, fmt.Errorf("sequence %d: %w", i, err)
+		}
+		likelihood, err := seqModel.totalProbability(alpha, true)
+		if err != nil {
+			return 0, fmt.Errorf("sequence %d: %w", i, err)
+		}
+		if math.IsInf(likelihood, -1) {
+			return 0, fmt.Errorf("sequence %d: %w", i, ErrPathBroken)
+		}
+		total += likelihood
+
+		for t := range seq {
+			unknown := t < len(seq)-1 && seqModel.unknownAt(t+1)
+			for _, s := range v.states {
+				alphaProb, ok := alpha[t][s]
+				if !ok {
+					continue
+				}
+				betaProb, ok := beta[t][s]
+				if !ok {
+					continue
+				}
+				gamma := math.Exp(alphaProb + betaProb - likelihood)
+				if t == 0 {
+					startCounts[s.ID()] += gamma
+				}
+				emKey := EmissionHash{s.ID(), seq[t].ID()}
+				if _, ok := v.emissionProbabilities[emKey]; ok {
+					emissionCounts[emKey] += gamma
+				}
+				emissionTotals[s.ID()] += gamma
+				if t == len(seq)-1 {
+					continue
+				}
+				transitionTotals[s.ID()] += gamma
+				for _, r := range v.states {
+					trKey := v.transitionKey(s, r)
+					if _, ok := v.transitionProbabilities[trKey]; !ok {
+						continue
+					}
+					nextBeta, ok := beta[t+1][r]
+					if !ok || !v.isPredecessor(s, r) {
+						continue
+					}
+					transitionProb, ok, err := seqModel.transitionProbability(s, r, true)
+					if err != nil || !ok {
+						continue
+					}
+					emissionProb, ok, err := seqModel.emissionProbability(r, t+1, unknown, true)
+					if err != nil || !ok {
+						continue
+					}
+					transitionCounts[trKey] += math.Exp(alphaProb + transitionProb + emissionProb + nextBeta - likelihood)
+				}
+			}
+		}
+	}
+
+	for id := range v.startProbabilities {
+		v.startProbabilities[id] = startCounts[id] / float64(len(sequences))
+	}
+	for key := range v.emissionProbabilities {
+		if emissionTotals[key.State] > 0 {
+			v.emissionProbabilities[key] = emissionCounts[key] / emissionTotals[key.State]
+		}
+	}
+	for key := range v.transitionProbabilities {
+		// Transitions are normalized over the source state, wherever it's placed in the key (see SetTransitionOrientation)
+		from := v.transitionSource(key)
+		if transitionTotals[from] > 0 {
+			v.transitionProbabilities[key] = transitionCounts[key] / transitionTotals[from]
+		}
+	}
+	return total, nil
+}
+
+// withProbabilities returns copy of the model where every start, emission and transition probability is replaced by fn(probability).
+// Results of emission and transition functions are transformed the same way
+func (v *Viterbi) withProbabilities(fn func(float64) float64) *Viterbi {
+	copied := *v
+	copied.states = append([]State{}, v.states...)
+	copied.observations = append([]Observation{}, v.observations...)
+	copied.stream = nil
+	copied.overwrites = v.Overwrites()
+	copied.startProbabilities = make(map[int]float64, len(v.startProbabilities))
+	for id, val := range v.startProbabilities {
+		copied.startProbabilities[id] = fn(val)
+	}
+	if v.endProbabilities != nil {
+		copied.endProbabilities = make(map[int]float64, len(v.endProbabilities))
+		for id, val := range v.endProbabilities {
+			copied.endProbabilities[id] = fn(val)
+		}
+	}
+	copied.emissionProbabilities = make(map[EmissionHash]float64, len(v.emissionProbabilities))
+	for key, val := range v.emissionProbabilities {
+		copied.emissionProbabilities[key] = fn(val)
+	}
+	copied.transitionProbabilities = make(map[TransitionHash]float64, len(v.transitionProbabilities))
+	for key, val := range v.transitionProbabilities {
+		copied.transitionProbabilities[key] = fn(val)
+	}
+	if v.transitionProbabilities2 != nil {
+		copied.transitionProbabilities2 = make(map[TransitionHash2]float64, len(v.transitionProbabilities2))
+		for key, val := range v.transitionProbabilities2 {
+			copied.transitionProbabilities2[key] = fn(val)
+		}
+	}
+	if v.durationProbabilities != nil {
+		copied.durationProbabilities = make(map[DurationHash]float64, len(v.durationProbabilities))
+		for key, val := range v.durationProbabilities {
+			copied.durationProbabilities[key] = fn(val)
+		}
+	}
+	if v.timedEmissions != nil {
+		copied.timedEmissions = make(map[TimedEmissionHash]float64, len(v.timedEmissions))
+		for key, val := range v.timedEmissions {
+			copied.timedEmissions[key] = fn(val)
+		}
+	}
+	if v.emissionFunc != nil {
+		emissionFunc := v.emissionFunc
+		copied.emissionFunc = func(s State, obs Observation) float64 {
+			return fn(emissionFunc(s, obs))
+		}
+	}
+	if v.transitionFunc != nil {
+		transitionFunc := v.transitionFunc
+		copied.transitionFunc = func(from, to State) (float64, bool) {
+			val, ok := transitionFunc(from, to)
+			return fn(val), ok
+		}
+	}
+	if v.predecessors != nil {
+		copied.predecessors = make(map[int][]State, len(v.predecessors))
+		for id, preds := range v.predecessors {
+			copied.predecessors[id] = append([]State{}, preds...)
+		}
+	}
+	if v.forbiddenTransitions != nil {
+		copied.forbiddenTransitions = make(map[TransitionHash]struct{}, len(v.forbiddenTransitions))
+		for key := range v.forbiddenTransitions {
+			copied.forbiddenTransitions[key] = struct{}{}
+		}
+	}
+	if v.constraints != nil {
+		copied.constraints = make(map[int]map[int]struct{}, len(v.constraints))
+		for t, ids := range v.constraints {
+			copied.constraints[t] = make(map[int]struct{}, len(ids))
+			for id := range ids {
+				copied.constraints[t][id] = struct{}{}
+			}
+		}
+	}
+	if v.noEmissions != nil {
+		copied.noEmissions = make(map[int]struct{}, len(v.noEmissions))
+		for t := range v.noEmissions {
+			copied.noEmissions[t] = struct{}{}
+		}
+	}
+	if v.unknownEmission != nil {
+		val := fn(*v.unknownEmission)
+		copied.unknownEmission = &val
+	}
+	if v.defaultEmission != nil {
+		val := fn(*v.defaultEmission)
+		copied.defaultEmission = &val
+	}
+	if v.defaultTransition != nil {
+		val := fn(*v.defaultTransition)
+		copied.defaultTransition = &val
+	}
+	// Counts are not probabilities yet, so they are copied as is
+	if v.startCounts != nil {
+		copied.startCounts = make(map[int]float64, len(v.startCounts))
+		for id, count := range v.startCounts {
+			copied.startCounts[id] = count
+		}
+	}
+	if v.emissionCounts != nil {
+		copied.emissionCounts = make(map[EmissionHash]float64, len(v.emissionCounts))
+		for key, count := range v.emissionCounts {
+			copied.emissionCounts[key] = count
+		}
+	}
+	if v.transitionCounts != nil {
+		copied.transitionCounts = make(map[TransitionHash]float64, len(v.transitionCounts))
+		for key, count := range v.transitionCounts {
+			copied.transitionCounts[key] = count
+		}
+	}
+	return &copied
+}
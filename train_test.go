@@ -0,0 +1,177 @@
+package viterbi
+
+import (
+	"math"
+	"math/rand"
+	"testing"
+)
+
+func TestViterbiTrain(t *testing.T) {
+	reference, incStates, incomingObservations := healthModel()
+	rng := rand.New(rand.NewSource(5))
+	sequences := [][]Observation{}
+	for i := 0; i < 20; i++ {
+		_, observations, err := reference.Sample(30, rng)
+		if err != nil {
+			t.Fatal(err)
+		}
+		sequences = append(sequences, observations)
+	}
+
+	// Start from uninformative parameters
+	v := New()
+	for i := range incStates {
+		v.AddState(incStates[i])
+	}
+	for i := range incomingObservations {
+		v.AddObservation(incomingObservations[i])
+	}
+	v.PutStartProbability(incStates[0], 0.5)
+	v.PutStartProbability(incStates[1], 0.5)
+	v.PutEmissionProbability(incStates[0], incomingObservations[0], 0.4)
+	v.PutEmissionProbability(incStates[0], incomingObservations[1], 0.3)
+	v.PutEmissionProbability(incStates[0], incomingObservations[2], 0.3)
+	v.PutEmissionProbability(incStates[1], incomingObservations[0], 0.3)
+	v.PutEmissionProbability(incStates[1], incomingObservations[1], 0.3)
+	v.PutEmissionProbability(incStates[1], incomingObservations[2], 0.4)
+	v.PutTransitionProbability(incStates[0], incStates[0], 0.5)
+	v.PutTransitionProbability(incStates[0], incStates[1], 0.5)
+	v.PutTransitionProbability(incStates[1], incStates[0], 0.5)
+	v.PutTransitionProbability(incStates[1], incStates[1], 0.5)
+
+	likelihoods, err := v.TrainWithLikelihoods(sequences, 50, 1e-9)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(likelihoods) < 2 {
+		t.Fatal(
+			"Expected several iterations, but got:", len(likelihoods),
+		)
+	}
+	// EM never decreases likelihood
+	for i := 1; i < len(likelihoods); i++ {
+		if likelihoods[i] < likelihoods[i-1]-1e-9 {
+			t.Error(
+				"Log-likelihood decreased at iteration", i, ":", likelihoods[i-1], "->", likelihoods[i],
+			)
+		}
+	}
+	if err := v.ValidateModel(); err != nil {
+		t.Error(
+			"Trained model has to be valid, but got", err,
+		)
+	}
+
+	if err := v.Train(nil, 10, 1e-9); err != ErrNoSequences {
+		t.Error(
+			"Error has to be ErrNoSequences, but got", err,
+		)
+	}
+}
+
+func TestViterbiTrainTransitionToFrom(t *testing.T) {
+	reference, incStates, incomingObservations := healthModel()
+	rng := rand.New(rand.NewSource(5))
+	sequences := [][]Observation{}
+	for i := 0; i < 10; i++ {
+		_, observations, err := reference.Sample(30, rng)
+		if err != nil {
+			t.Fatal(err)
+		}
+		sequences = append(sequences, observations)
+	}
+
+	initial := func() *Viterbi {
+		v := New()
+		for i := range incStates {
+			v.AddState(incStates[i])
+		}
+		for i := range incomingObservations {
+			v.AddObservation(incomingObservations[i])
+		}
+		v.PutStartProbability(incStates[0], 0.5)
+		v.PutStartProbability(incStates[1], 0.5)
+		v.PutEmissionProbability(incStates[0], incomingObservations[0], 0.4)
+		v.PutEmissionProbability(incStates[0], incomingObservations[1], 0.3)
+		v.PutEmissionProbability(incStates[0], incomingObservations[2], 0.3)
+		v.PutEmissionProbability(incStates[1], incomingObservations[0], 0.3)
+		v.PutEmissionProbability(incStates[1], incomingObservations[1], 0.3)
+		v.PutEmissionProbability(incStates[1], incomingObservations[2], 0.4)
+		return v
+	}
+	expected := initial()
+	expected.PutTransitionProbability(incStates[0], incStates[0], 0.6)
+	expected.PutTransitionProbability(incStates[0], incStates[1], 0.4)
+	expected.PutTransitionProbability(incStates[1], incStates[0], 0.3)
+	expected.PutTransitionProbability(incStates[1], incStates[1], 0.7)
+	if err := expected.Train(sequences, 5, 1e-9); err != nil {
+		t.Fatal(err)
+	}
+
+	// The same transitions stored transposed
+	v := initial()
+	v.SetTransitionOrientation(TransitionToFrom)
+	v.PutTransitionProbability(incStates[0], incStates[0], 0.6)
+	v.PutTransitionProbability(incStates[1], incStates[0], 0.4)
+	v.PutTransitionProbability(incStates[0], incStates[1], 0.3)
+	v.PutTransitionProbability(incStates[1], incStates[1], 0.7)
+	if err := v.Train(sequences, 5, 1e-9); err != nil {
+		t.Fatal(err)
+	}
+	for key, val := range expected.transitionProbabilities {
+		transposed := v.transitionProbabilities[TransitionHash{From: key.To, To: key.From}]
+		if math.Abs(transposed-val) > 1e-9 {
+			t.Error(
+				"Transition", key, "has to be re-estimated as", val, ", but got", transposed,
+			)
+		}
+	}
+}
+
+func TestViterbiTrainIgnoresObservationMarks(t *testing.T) {
+	reference, incStates, _ := healthModel()
+	rng := rand.New(rand.NewSource(5))
+	sequences := [][]Observation{}
+	for i := 0; i < 10; i++ {
+		_, observations, err := reference.Sample(30, rng)
+		if err != nil {
+			t.Fatal(err)
+		}
+		sequences = append(sequences, observations)
+	}
+
+	expected, _, _ := healthModel()
+	expectedLikelihoods, err := expected.TrainWithLikelihoods(sequences, 5, 1e-9)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Marks of observations of the model have not to be applied to training sequences
+	v, _, _ := healthModel()
+	v.AddConstraint(0, []State{incStates[1]})
+	v.PutEmissionProbabilityAt(incStates[0], 1, 0.9)
+	v.PutNoEmissionAt(2)
+	likelihoods, err := v.TrainWithLikelihoods(sequences, 5, 1e-9)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(likelihoods) != len(expectedLikelihoods) {
+		t.Fatal(
+			"Expected", len(expectedLikelihoods), "iterations, but got:", len(likelihoods),
+		)
+	}
+	for i := range likelihoods {
+		if math.Abs(likelihoods[i]-expectedLikelihoods[i]) > 1e-9 {
+			t.Error(
+				"Log-likelihood at iteration", i, "has to be", expectedLikelihoods[i], ", but got", likelihoods[i],
+			)
+		}
+	}
+	for key, val := range expected.transitionProbabilities {
+		if math.Abs(v.transitionProbabilities[key]-val) > 1e-9 {
+			t.Error(
+				"Transition", key, "has to be re-estimated as", val, ", but got", v.transitionProbabilities[key],
+			)
+		}
+	}
+}
@@ -0,0 +1,188 @@
+package viterbi
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"math"
+	"strings"
+)
+
+// TrellisCell is single evaluated cell of Viterbi trellis
+type TrellisCell struct {
+	// State of the cell
+	State State
+	// Probability of the most probable path ending in the state
+	Probability float64
+	// Previous is back-pointer: state of previous observation on the most probable path (nil for the first observation)
+	Previous State
+}
+
+// EvalPathWithTrellis is the same as EvalPath, but returns trellis alongside the best path.
+// Trellis contains cell for every reachable state of every observation; cells are ordered as states have been added
+// When every probability is in [0;1]
+func (v *Viterbi) EvalPathWithTrellis() (ViterbiPath, [][]TrellisCell, error) {
+	return v.evalPathWithTrellis(false)
+}
+
+// EvalPathWithTrellisLogProbabilities is the same as EvalPathWithTrellis, but when every probability is logarithmic
+func (v *Viterbi) EvalPathWithTrellisLogProbabilities() (ViterbiPath, [][]TrellisCell, error) {
+	return v.evalPathWithTrellis(true)
+}
+
+func (v *Viterbi) evalPathWithTrellis(logSpace bool) (ViterbiPath, [][]TrellisCell, error) {
+	logScales := make([]float64, len(v.observations))
+	V, logScale, err := v.evalTrellis(context.Background(), evalOptions{logSpace: logSpace, logScales: logScales})
+	if err != nil {
+		return ViterbiPath{}, nil, err
+	}
+	path, err := v.backtrack(V, logSpace, logScale)
+	if err != nil {
+		return ViterbiPath{}, nil, err
+	}
+	return path, v.exportTrellis(V, logSpace, logScales), nil
+}
+
+// FinalStates evaluates trellis and returns every state reachable at the last observation together with probability
+// of the most probable path ending in it (end probability included), i.e. terminal distribution of decode. The best of them is probability of EvalPath.
+// States with zero probability are omitted
+// When every probability is in [0;1]
+func (v *Viterbi) FinalStates() (map[State]float64, error) {
+	return v.finalStates(false)
+}
+
+// FinalStatesLogProbabilities is the same as FinalStates, but when every probability is logarithmic (returned ones are logarithmic too)
+func (v *Viterbi) FinalStatesLogProbabilities() (map[State]float64, error) {
+	return v.finalStates(true)
+}
+
+func (v *Viterbi) finalStates(logSpace bool) (map[State]float64, error) {
+	V, logScale, err := v.evalTrellis(context.Background(), evalOptions{logSpace: logSpace})
+	if err != nil {
+		return nil, err
+	}
+	final := make(map[State]float64, len(V[len(V)-1]))
+	for st, value := range V[len(V)-1] {
+		endProb, err := v.endProbability(st, logSpace)
+		if err != nil {
+			return nil, err
+		}
+		prob := combine(logSpace, value.prob, endProb)
+		if !logSpace {
+			prob *= math.Exp(logScale)
+		}
+		if impossible(logSpace, prob) {
+			continue
+		}
+		final[st] = prob
+	}
+	return final, nil
+}
+
+// ActiveStatesAt returns states reachable at observation t (ones having trellis cell, after pruning if it's enabled) in order of adding.
+// Only observations up to t are evaluated, so it's cheaper than EvalPathWithTrellis when support of single column is needed
+// When every probability is in [0;1]
+func (v *Viterbi) ActiveStatesAt(t int) ([]State, error) {
+	return v.activeStatesAt(t, false)
+}
+
+// ActiveStatesAtLogProbabilities is the same as ActiveStatesAt, but when every probability is logarithmic
+func (v *Viterbi) ActiveStatesAtLogProbabilities(t int) ([]State, error) {
+	return v.activeStatesAt(t, true)
+}
+
+func (v *Viterbi) activeStatesAt(t int, logSpace bool) ([]State, error) {
+	if err := v.validate(); err != nil {
+		return nil, err
+	}
+	if t < 0 || t >= len(v.observations) {
+		return nil, fmt.Errorf("%w: %d for %d observations", ErrInvalidTimestep, t, len(v.observations))
+	}
+	prefix := *v
+	prefix.observations = v.observations[:t+1]
+	V, _, err := prefix.evalTrellis(context.Background(), evalOptions{logSpace: logSpace})
+	if err != nil {
+		return nil, err
+	}
+	active := make([]State, 0, len(V[t]))
+	for _, st := range v.states {
+		if _, ok := V[t][st]; ok {
+			active = append(active, st)
+		}
+	}
+	return active, nil
+}
+
+// exportTrellis converts internal trellis into cells ordered as states have been added.
+// Scaling of classic probabilities is undone (in logarithmic form, like backtrack does) with logScales accumulated up to every column
+func (v *Viterbi) exportTrellis(V []map[State]ViterbiVal, logSpace bool, logScales []float64) [][]TrellisCell {
+	trellis := make([][]TrellisCell, len(V))
+	for t := range V {
+		trellis[t] = make([]TrellisCell, 0, len(V[t]))
+		for _, st := range v.states {
+			value, ok := V[t][st]
+			if !ok {
+				continue
+			}
+			prob := value.prob
+			if !logSpace && logScales[t] != 0 {
+				prob = math.Exp(math.Log(prob) + logScales[t])
+			}
+			trellis[t] = append(trellis[t], TrellisCell{State: st, Probability: prob, Previous: value.prev})
+		}
+	}
+	return trellis
+}
+
+// PrintTrellis writes trellis (see EvalPathWithTrellis) as table to w: one column per observation and one row per state.
+// Unreachable cells are printed as "-"
+func PrintTrellis(w io.Writer, trellis [][]TrellisCell) error {
+	return PrintTrellisWith(w, trellis, func(st State) string {
+		return fmt.Sprint(st)
+	})
+}
+
+// PrintTrellisWith is the same as PrintTrellis, but every state is rendered by label
+func PrintTrellisWith(w io.Writer, trellis [][]TrellisCell, label func(st State) string) error {
+	states := []State{}
+	seen := make(map[int]struct{})
+	for t := range trellis {
+		for _, cell := range trellis[t] {
+			if _, ok := seen[cell.State.ID()]; !ok {
+				seen[cell.State.ID()] = struct{}{}
+				states = append(states, cell.State)
+			}
+		}
+	}
+
+	var b strings.Builder
+	b.WriteString("      ")
+	for t := range trellis {
+		fmt.Fprintf(&b, "%9d ", t)
+	}
+	b.WriteString("\n")
+	for _, st := range states {
+		fmt.Fprintf(&b, "%-5.5s: ", label(st))
+		for t := range trellis {
+			cell, ok := findCell(trellis[t], st)
+			if !ok {
+				fmt.Fprintf(&b, "%9s ", "-")
+				continue
+			}
+			fmt.Fprintf(&b, "%9.5f ", cell.Probability)
+		}
+		b.WriteString("\n")
+	}
+	_, err := io.WriteString(w, b.String())
+	return err
+}
+
+// findCell returns cell of the state in trellis column
+func findCell(column []TrellisCell, st State) (TrellisCell, bool) {
+	for _, cell := range column {
+		if cell.State.ID() == st.ID() {
+			return cell, true
+		}
+	}
+	return TrellisCell{}, false
+}
@@ -0,0 +1,175 @@
+package viterbi
+
+import (
+	"bytes"
+	"errors"
+	"math"
+	"strings"
+	"testing"
+)
+
+func TestViterbiEvalPathWithTrellis(t *testing.T) {
+	v, incStates, incomingObservations := healthModel()
+	vpath, trellis, err := v.EvalPathWithTrellis()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if vpath.Probability != 0.01512 {
+		t.Error(
+			"Probability has to be 0.01512, but got", vpath.Probability,
+		)
+	}
+	if len(trellis) != len(incomingObservations) {
+		t.Fatal(
+			"Expected", len(incomingObservations), "time steps, but got:", len(trellis),
+		)
+	}
+	// Values of the classic example (https://en.wikipedia.org/wiki/Viterbi_algorithm#Example)
+	expected := [][]TrellisCell{
+		{{State: incStates[0], Probability: 0.3}, {State: incStates[1], Probability: 0.04}},
+		{{State: incStates[0], Probability: 0.084, Previous: incStates[0]}, {State: incStates[1], Probability: 0.027, Previous: incStates[0]}},
+		{{State: incStates[0], Probability: 0.00588, Previous: incStates[0]}, {State: incStates[1], Probability: 0.01512, Previous: incStates[0]}},
+	}
+	for step := range expected {
+		if len(trellis[step]) != len(expected[step]) {
+			t.Error(
+				"Expected", len(expected[step]), "cells at", step, "but got:", len(trellis[step]),
+			)
+			continue
+		}
+		for i := range expected[step] {
+			cell := trellis[step][i]
+			if cell.State != expected[step][i].State || cell.Previous != expected[step][i].Previous {
+				t.Error(
+					"Cell", i, "at", step, "has to be", expected[step][i], "but got", cell,
+				)
+			}
+			if diff := cell.Probability - expected[step][i].Probability; diff > 1e-12 || diff < -1e-12 {
+				t.Error(
+					"Probability of cell", i, "at", step, "has to be", expected[step][i].Probability, "but got", cell.Probability,
+				)
+			}
+		}
+	}
+}
+
+func TestViterbiEvalPathWithTrellisRescaled(t *testing.T) {
+	// Columns of 80 observations are rescaled, but exported probabilities are still representable
+	v := randomModel(5, 80, 3)
+	vpath, trellis, err := v.EvalPathWithTrellis()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if vpath.Probability == 0 || vpath.LogProbability > math.Log(rescaleThreshold) {
+		t.Fatal(
+			"Path has to be long enough to rescale trellis, but got", vpath.LogProbability,
+		)
+	}
+	best := 0.0
+	for _, cell := range trellis[len(trellis)-1] {
+		best = math.Max(best, cell.Probability)
+	}
+	if math.Abs(best-vpath.Probability) > 1e-9*vpath.Probability {
+		t.Error(
+			"The best cell of the last observation has to be", vpath.Probability, ", but got", best,
+		)
+	}
+}
+
+func TestPrintTrellis(t *testing.T) {
+	v, _, _ := healthModel()
+	_, trellis, err := v.EvalPathWithTrellis()
+	if err != nil {
+		t.Fatal(err)
+	}
+	var buf bytes.Buffer
+	if err := PrintTrellis(&buf, trellis); err != nil {
+		t.Fatal(err)
+	}
+	lines := strings.Split(strings.TrimSuffix(buf.String(), "\n"), "\n")
+	// Header and one row per state
+	if len(lines) != 3 {
+		t.Fatal(
+			"Number of lines has to be 3, but got", len(lines), buf.String(),
+		)
+	}
+	for _, val := range []string{"0.30000", "0.08400", "0.00588"} {
+		if !strings.Contains(lines[1], val) {
+			t.Error(
+				"Row of 'Healty' has to contain", val, ", but got", lines[1],
+			)
+		}
+	}
+	for _, val := range []string{"0.04000", "0.02700", "0.01512"} {
+		if !strings.Contains(lines[2], val) {
+			t.Error(
+				"Row of 'Fever' has to contain", val, ", but got", lines[2],
+			)
+		}
+	}
+}
+
+func TestViterbiFinalStates(t *testing.T) {
+	v, incStates, _ := healthModel()
+	final, err := v.FinalStates()
+	if err != nil {
+		t.Fatal(err)
+	}
+	// 'Healthy': 0.084·0.7·0.1, 'Fever': 0.084·0.3·0.6
+	expected := map[State]float64{incStates[0]: 0.00588, incStates[1]: 0.01512}
+	if len(final) != len(expected) {
+		t.Fatal(
+			"Expected", len(expected), "final states, but got", final,
+		)
+	}
+	for st, prob := range expected {
+		if math.Abs(final[st]-prob) > 1e-12 {
+			t.Error(
+				"Probability of", st, "has to be", prob, ", but got", final[st],
+			)
+		}
+	}
+
+	logFinal, err := v.ToLog().FinalStatesLogProbabilities()
+	if err != nil {
+		t.Fatal(err)
+	}
+	for st, prob := range expected {
+		if math.Abs(logFinal[st]-math.Log(prob)) > 1e-12 {
+			t.Error(
+				"Log probability of", st, "has to be", math.Log(prob), ", but got", logFinal[st],
+			)
+		}
+	}
+}
+
+func TestViterbiActiveStatesAt(t *testing.T) {
+	v, incStates, _ := healthModel()
+	active, err := v.ActiveStatesAt(1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(active) != 2 || active[0] != incStates[0] || active[1] != incStates[1] {
+		t.Error(
+			"Both states have to be active, but got", active,
+		)
+	}
+
+	// Beam keeps single state per observation: 'Fever' wins at the last one
+	v.SetBeamWidth(1)
+	active, err = v.ToLog().ActiveStatesAtLogProbabilities(2)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(active) != 1 || active[0] != incStates[1] {
+		t.Error(
+			"Only 'Fever' has to be active, but got", active,
+		)
+	}
+
+	if _, err := v.ActiveStatesAt(3); !errors.Is(err, ErrInvalidTimestep) {
+		t.Error(
+			"Error has to be ErrInvalidTimestep, but got", err,
+		)
+	}
+}
@@ -0,0 +1,167 @@
+package viterbi
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"sort"
+	"strings"
+)
+
+var (
+	// ErrUnknownState is returned when probability references state which has not been added
+	ErrUnknownState = errors.New("state has not been added")
+	// ErrUnknownObservation is returned when probability references observation which has not been added
+	ErrUnknownObservation = errors.New("observation has not been added")
+	// ErrStartProbabilitiesSum is returned when start probabilities do not sum to 1
+	ErrStartProbabilitiesSum = errors.New("start probabilities have to sum to 1")
+)
+
+// sumTolerance is allowed deviation of probabilities sum from 1
+const sumTolerance = 1e-6
+
+// ValidationError contains every problem found by ValidateModel
+type ValidationError struct {
+	Problems []error
+}
+
+func (e *ValidationError) Error() string {
+	msgs := make([]string, len(e.Problems))
+	for i := range e.Problems {
+		msgs[i] = e.Problems[i].Error()
+	}
+	return fmt.Sprintf("model is invalid: %s", strings.Join(msgs, "; "))
+}
+
+// Unwrap returns every found problem, so errors.Is and errors.As could be used for them
+func (e *ValidationError) Unwrap() []error {
+	return e.Problems
+}
+
+// Is reports whether any of found problems matches target
+func (e *ValidationError) Is(target error) bool {
+	for _, problem := range e.Problems {
+		if errors.Is(problem, target) {
+			return true
+		}
+	}
+	return false
+}
+
+// ValidateModel checks model before evaluation: every probability has to be in [0;1] range,
+// every referenced state and observation has to be added and start probabilities have to sum to 1.
+// Recorded overwrites of probabilities are reported too (see SetOverwritePolicy).
+// It returns *ValidationError listing every found problem or nil
+// When every probability is in [0;1]
+func (v *Viterbi) ValidateModel() error {
+	return v.validateModel(false)
+}
+
+// ValidateModelLogProbabilities is the same as ValidateModel, but when every probability is logarithmic (has to be ≤ 0)
+func (v *Viterbi) ValidateModelLogProbabilities() error {
+	return v.validateModel(true)
+}
+
+func (v *Viterbi) validateModel(logSpace bool) error {
+	problems := []error{}
+	knownStates := make(map[int]struct{}, len(v.states))
+	for _, st := range v.states {
+		knownStates[st.ID()] = struct{}{}
+	}
+	knownObservations := make(map[int]struct{}, len(v.observations))
+	for _, obs := range v.observations {
+		knownObservations[obs.ID()] = struct{}{}
+	}
+	checkState := func(id int) {
+		if _, ok := knownStates[id]; !ok {
+			problems = append(problems, fmt.Errorf("%w: ID %d", ErrUnknownState, id))
+		}
+	}
+	// Densities have no upper bound (see SetEmissionsAreDensities)
+	checkRange := func(val float64, density bool, format string, args ...interface{}) {
+		_, valid := v.clampProbability(false, val)
+		if density {
+			valid = val >= 0 && !math.IsInf(val, 1)
+		}
+		if logSpace {
+			valid = val <= 0 || density && !math.IsNaN(val)
+		}
+		if !valid {
+			problems = append(problems, fmt.Errorf("%w: "+format, append([]interface{}{ErrInvalidProbability}, args...)...))
+		}
+	}
+
+	startProbs := []float64{}
+	for st, val := range v.startProbabilities {
+		checkState(st)
+		checkRange(val, false, "start probability %v of state with ID %d", val, st)
+		startProbs = append(startProbs, val)
+	}
+	if len(startProbs) != 0 {
+		sort.Float64s(startProbs)
+		sum := sumProbabilities(logSpace, startProbs...)
+		if logSpace {
+			sum = math.Exp(sum)
+		}
+		if math.Abs(sum-1) > sumTolerance {
+			problems = append(problems, fmt.Errorf("%w: got %v", ErrStartProbabilitiesSum, sum))
+		}
+	}
+	for key, val := range v.emissionProbabilities {
+		checkState(key.State)
+		if _, ok := knownObservations[key.observation]; !ok {
+			problems = append(problems, fmt.Errorf("%w: ID %d", ErrUnknownObservation, key.observation))
+		}
+		checkRange(val, v.emissionDensities, "emission probability %v of state with ID %d for observation with ID %d", val, key.State, key.observation)
+	}
+	for key, val := range v.transitionProbabilities {
+		checkState(key.From)
+		checkState(key.To)
+		checkRange(val, false, "transition probability %v from state with ID %d to state with ID %d", val, key.From, key.To)
+	}
+
+	problems = append(problems, v.overwrites...)
+
+	if len(problems) == 0 {
+		return nil
+	}
+	// Maps are iterated in random order, so sort problems to make output reproducible
+	sort.Slice(problems, func(i, j int) bool {
+		return problems[i].Error() < problems[j].Error()
+	})
+	return &ValidationError{Problems: problems}
+}
+
+// CheckEmissionSums returns sums of stored emission probabilities of states whose emissions do not sum to 1 within tol
+// (e.g. when some observation has been forgotten). States without emissions have zero sum
+// When every probability is in [0;1]
+func (v *Viterbi) CheckEmissionSums(tol float64) map[State]float64 {
+	return v.checkEmissionSums(false, tol)
+}
+
+// CheckEmissionSumsLogProbabilities is the same as CheckEmissionSums, but when every probability is logarithmic.
+// Sums are returned as classic probabilities
+func (v *Viterbi) CheckEmissionSumsLogProbabilities(tol float64) map[State]float64 {
+	return v.checkEmissionSums(true, tol)
+}
+
+func (v *Viterbi) checkEmissionSums(logSpace bool, tol float64) map[State]float64 {
+	emissions := make(map[int][]float64, len(v.states))
+	for key, val := range v.emissionProbabilities {
+		emissions[key.State] = append(emissions[key.State], val)
+	}
+	sums := make(map[State]float64)
+	for _, st := range v.states {
+		probs := emissions[st.ID()]
+		// Sum in fixed order to make result reproducible
+		sort.Float64s(probs)
+		sum := sumProbabilities(logSpace, probs...)
+		if logSpace {
+			sum = math.Exp(sum)
+		}
+		if math.Abs(sum-1) > tol {
+			sums[st] = sum
+		}
+	}
+	return sums
+}
@@ -0,0 +1,80 @@
+package viterbi
+
+import (
+	"errors"
+	"math"
+	"testing"
+)
+
+func TestViterbiValidateModel(t *testing.T) {
+	v, incStates, _ := healthModel()
+	if err := v.ValidateModel(); err != nil {
+		t.Error(
+			"Classic example has to be valid, but got", err,
+		)
+	}
+	if err := logModel(v).ValidateModelLogProbabilities(); err != nil {
+		t.Error(
+			"Logarithmic classic example has to be valid, but got", err,
+		)
+	}
+
+	unknown := CustomState{Name: "Unknown", id: 3}
+	v.PutTransitionProbability(incStates[0], unknown, 1.5)
+	v.PutEmissionProbability(incStates[1], CustomObservation{Name: "unknown", id: 4}, 0.1)
+	v.PutStartProbability(incStates[1], 0.5)
+
+	err := v.ValidateModel()
+	var validationErr *ValidationError
+	if !errors.As(err, &validationErr) {
+		t.Fatal(
+			"Error has to be *ValidationError, but got", err,
+		)
+	}
+	if len(validationErr.Problems) != 4 {
+		t.Error(
+			"Expected 4 problems, but got:", validationErr.Problems,
+		)
+	}
+	for _, target := range []error{ErrUnknownState, ErrUnknownObservation, ErrInvalidProbability, ErrStartProbabilitiesSum} {
+		if !errors.Is(err, target) {
+			t.Error(
+				"Error has to contain", target, "but got", err,
+			)
+		}
+	}
+}
+
+func TestViterbiCheckEmissionSums(t *testing.T) {
+	v, incStates, _ := healthModel()
+	if sums := v.CheckEmissionSums(sumTolerance); len(sums) != 0 {
+		t.Error(
+			"Emissions of classic example have to sum to 1, but got", sums,
+		)
+	}
+	if sums := logModel(v).CheckEmissionSumsLogProbabilities(sumTolerance); len(sums) != 0 {
+		t.Error(
+			"Logarithmic emissions of classic example have to sum to 1, but got", sums,
+		)
+	}
+
+	unknown := CustomState{Name: "Unknown", id: 3}
+	v.AddState(unknown)
+	v.RemoveObservation(CustomObservation{Name: "dizzy", id: 3})
+	sums := v.CheckEmissionSums(sumTolerance)
+	if len(sums) != 3 {
+		t.Fatal(
+			"Expected 3 states, but got:", sums,
+		)
+	}
+	if math.Abs(sums[incStates[0]]-0.9) > 1e-12 || math.Abs(sums[incStates[1]]-0.4) > 1e-12 || sums[unknown] != 0 {
+		t.Error(
+			"Sums have to be 0.9, 0.4 and 0, but got", sums,
+		)
+	}
+	if sums := v.CheckEmissionSums(0.7); len(sums) != 1 || sums[unknown] != 0 {
+		t.Error(
+			"Only state without emissions has to be out of 0.7 tolerance, but got", sums,
+		)
+	}
+}
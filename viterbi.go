@@ -1,276 +1,1035 @@
 package viterbi
 
 import (
+	"context"
+	"errors"
 	"fmt"
 	"math"
 )
 
+var (
+	// ErrNoStates is returned when evaluation is called for model without states
+	ErrNoStates = errors.New("no states have been added")
+	// ErrNoObservations is returned when evaluation is called for model without observations
+	ErrNoObservations = errors.New("no observations have been added")
+	// ErrInvalidProbability is returned when classic probability is not in [0;1] range or any probability is NaN
+	ErrInvalidProbability = errors.New("probability has to be in [0;1] range")
+	// ErrNilState is returned by AddStateChecked (other methods panic with it) when nil State is passed to the model
+	ErrNilState = errors.New("state is nil")
+	// ErrNilObservation is returned by AddObservationChecked (other methods panic with it) when nil Observation is passed to the model
+	ErrNilObservation = errors.New("observation is nil")
+	// ErrDuplicateState is returned when state with the same ID() has been added already
+	ErrDuplicateState = errors.New("duplicate state ID")
+	// ErrDuplicateObservation is returned when observation with the same ID() has been added already
+	ErrDuplicateObservation = errors.New("duplicate observation ID")
+	// ErrNoValidPath is returned when there is no path with non-zero probability. Every *PathBrokenError matches it too
+	ErrNoValidPath = errors.New("no valid path: every path has zero probability")
+	// ErrMissingTransition is returned in strict mode when there is no transition probability between reachable states (see SetStrictTransitions)
+	ErrMissingTransition = errors.New("missing transition probability")
+	// ErrPathBroken is returned when no state could be reached for some observation (evaluators wrap it into *PathBrokenError)
+	ErrPathBroken = errors.New("path is broken: no state is reachable for observation")
+)
+
+// State is hidden state of the model. ID() is identity of the state: values with the same ID() are the same state
+// wherever model is given a state (probabilities, predecessors, constraints and so on), so value-semantic states are safe to use.
+// Added values themselves are used as keys of trellis columns, so their dynamic types have to be comparable
 type State interface {
 	ID() int
 }
+
+// Observation is observable symbol. As for State, ID() is identity of the observation
 type Observation interface {
 	ID() int
 }
 
+// TransitionHash is key of transition probability: IDs of source and destination states
 type TransitionHash struct {
-	From State
-	To   State
+	From int
+	To   int
 }
 
+// EmissionHash is key of emission probability: IDs of state and observation
 type EmissionHash struct {
-	State       State
-	observation Observation
+	State       int
+	observation int
 }
 
-type Viterbi struct {
-	states                  []State
-	observations            []Observation
-	startProbabilities      map[State]float64
+// TimedEmissionHash is key of time-indexed emission probability: ID of state and index of observation
+type TimedEmissionHash struct {
+	State int
+	T     int
+}
+
+// Model is parameters of Hidden Markov Model: states together with start, emission and transition probabilities.
+// Probabilities are keyed by ID() of states and observations, so different values with the same ID() are the same state (observation).
+// Model does not hold observations sequence: it's decoded via Decode (or by Viterbi which embeds Model)
+type Model struct {
+	states             []State
+	startProbabilities map[int]float64
+	// endProbabilities are probabilities of the path to end in the state (see PutEndProbability)
+	endProbabilities        map[int]float64
 	emissionProbabilities   map[EmissionHash]float64
 	transitionProbabilities map[TransitionHash]float64
+	// transitionProbabilities2 are second-order transition probabilities (see PutTransitionProbability2)
+	transitionProbabilities2 map[TransitionHash2]float64
+	// durationProbabilities are probabilities of states to last given number of observations (see SetDurationProbability)
+	durationProbabilities map[DurationHash]float64
+	// emissionFunc evaluates emission probability for pairs of state and observation without stored one (when set)
+	emissionFunc func(s State, obs Observation) float64
+	// unknownEmission is used for observations without emission probability for every state (when set)
+	unknownEmission *float64
+	// defaultEmission is used for pairs of state and observation without emission probability (when set)
+	defaultEmission *float64
+	// predecessors are the only states transition to the state with given ID is evaluated from (see SetPredecessors)
+	predecessors map[int][]State
+	// tieBreaker reports whether state a has to be chosen over state b of equal probability (when set, see SetTieBreaker)
+	tieBreaker func(a, b State) bool
+	// transitionFunc evaluates transition probability for pairs of states without stored one (when set)
+	transitionFunc func(from, to State) (float64, bool)
+	// transitionOrientation defines how stored transition probabilities are keyed (see SetTransitionOrientation)
+	transitionOrientation TransitionOrientation
+	// defaultTransition is used for pairs of states without transition probability (when set)
+	defaultTransition *float64
+	// forbiddenTransitions are impossible regardless of stored, default and computed probabilities (see ForbidTransition)
+	forbiddenTransitions map[TransitionHash]struct{}
+	// strictTransitions turns missing transition probability into error (see SetStrictTransitions)
+	strictTransitions bool
+	// emissionWeight and transitionWeight are exponents of emission and transition probabilities (when set)
+	emissionWeight   *float64
+	transitionWeight *float64
+	// beamWidth is maximum number of states kept in every trellis column (0 means no pruning)
+	beamWidth int
+	// pruneRatio is fraction of the best probability of trellis column below which states are pruned (0 means no pruning)
+	pruneRatio float64
+	// ambiguityEpsilon is how close runner-up state has to be for observation to be marked ambiguous (when set)
+	ambiguityEpsilon *float64
+	// minProbability is probability the best partial path could not fall below (when set)
+	minProbability *float64
+	// startCounts, emissionCounts and transitionCounts are accumulated by Inc* methods until Finalize
+	startCounts      map[int]float64
+	emissionCounts   map[EmissionHash]float64
+	transitionCounts map[TransitionHash]float64
+	// expectedActive is capacity of trellis column allocated for every observation (see SetExpectedActiveStates)
+	expectedActive int
+	// tolerance is how far classic probability could be out of [0;1] range before it's rejected
+	tolerance float64
+	// emissionDensities disables upper bound of emission probabilities (see SetEmissionsAreDensities)
+	emissionDensities bool
+	// overwritePolicy defines reaction to probability put again with different value, recorded conflicts are kept in overwrites
+	overwritePolicy OverwritePolicy
+	overwrites      []error
+}
+
+// Viterbi is Hidden Markov Model together with observations sequence to be decoded.
+// Methods changing the model (Add*, Put*, Remove*, Set* and so on) are not safe for concurrent use.
+// Evaluators (EvalPath*, Forward*, Backward*, EvalPosterior* and so on) read the model only, so several goroutines could evaluate the same instance
+// at the same time, but not while the model or observations are changed (copy of the instance shares its maps, so it's not isolated: see Clone).
+// Configured model could be shared read-only between goroutines decoding different sequences via EvalSequence
+type Viterbi struct {
+	Model
+	observations []Observation
+	// stream is state of online evaluation started by Begin
+	stream *streamState
+	// constraints are IDs of states allowed for observation with given index (see AddConstraint)
+	constraints map[int]map[int]struct{}
+	// timedEmissions are emission probabilities for observation with given index (see PutEmissionProbabilityAt)
+	timedEmissions map[TimedEmissionHash]float64
+	// noEmissions are indices of observations without emission (see PutNoEmissionAt)
+	noEmissions map[int]struct{}
 }
 
 type ViterbiPath struct {
 	Probability float64
-	Path        []State
+	// LogProbability is logarithm of Probability (or Probability itself when every probability is logarithmic).
+	// It stays finite on long sequences even when Probability underflows to zero
+	LogProbability float64
+	Path           []State
+	// StepProbabilities are local probabilities of the path at every observation: start·emission for the first one and transition·emission for the rest
+	// (sums when every probability is logarithmic); end probability is not included. It's filled by Viterbi evaluators only
+	StepProbabilities []float64
+	// Ambiguous marks observations where state of the path has beaten runner-up state by no more than ambiguity epsilon
+	// (see SetAmbiguityEpsilon). It's filled by Viterbi evaluators only when ambiguity epsilon is set
+	Ambiguous []bool
 }
 
+// ViterbiVal is evaluated trellis cell: probability of the most probable path ending in the state and back-pointer to previous state of it
 type ViterbiVal struct {
 	prob float64
 	prev State
 }
 
+// Prob returns probability of the most probable path ending in the cell
+func (val ViterbiVal) Prob() float64 {
+	return val.prob
+}
+
+// Prev returns state of previous observation on the most probable path ending in the cell (nil for the first observation)
+func (val ViterbiVal) Prev() State {
+	return val.prev
+}
+
 func New() *Viterbi {
 	return &Viterbi{
-		startProbabilities:      make(map[State]float64),
+		Model: *NewModel(),
+	}
+}
+
+// NewModel returns empty model
+func NewModel() *Model {
+	return &Model{
+		startProbabilities:      make(map[int]float64),
 		emissionProbabilities:   make(map[EmissionHash]float64),
 		transitionProbabilities: make(map[TransitionHash]float64),
 	}
 }
 
-func (v *Viterbi) AddState(s State) {
-	v.states = append(v.states, s)
+// AddState adds state to the model.
+// States are identified by ID(): probabilities put for different values with the same ID() belong to the same state.
+// Use AddStateChecked to prevent adding the same ID() twice
+func (m *Model) AddState(s State) {
+	mustState(s, "AddState")
+	if m.predecessors != nil {
+		if _, ok := m.stateWithID(s.ID()); !ok {
+			m.resolvePredecessors(s)
+		}
+	}
+	m.states = append(m.states, s)
+}
+
+// stateWithID returns the first added state with given ID and whether it has been found
+func (m *Model) stateWithID(id int) (State, bool) {
+	for _, st := range m.states {
+		if st.ID() == id {
+			return st, true
+		}
+	}
+	return nil, false
+}
+
+// resolvePredecessors replaces predecessors having the same ID() as just added state by the state itself,
+// so they match keys of trellis columns even when they are different values
+func (m *Model) resolvePredecessors(s State) {
+	for _, preds := range m.predecessors {
+		for i := range preds {
+			if preds[i].ID() == s.ID() {
+				preds[i] = s
+			}
+		}
+	}
+}
+
+// AddStateChecked is the same as AddState, but returns ErrDuplicateState when state with the same ID() has been added already
+func (m *Model) AddStateChecked(s State) error {
+	if isNil(s) {
+		return ErrNilState
+	}
+	for _, st := range m.states {
+		if st.ID() == s.ID() {
+			return fmt.Errorf("%w: %v has the same ID %d as %v", ErrDuplicateState, s, s.ID(), st)
+		}
+	}
+	m.AddState(s)
+	return nil
 }
 
+// AddObservation appends observation to the sequence to be decoded by EvalPath. It's not safe for concurrent use: see EvalSequence
 func (v *Viterbi) AddObservation(obs Observation) {
+	mustObservation(obs, "AddObservation")
 	v.observations = append(v.observations, obs)
 }
 
-func (v *Viterbi) PutStartProbability(state State, val float64) {
-	if v.startProbabilities == nil {
-		v.startProbabilities = make(map[State]float64)
+// AddObservationChecked is the same as AddObservation, but returns ErrDuplicateObservation when observation with the same ID() has been added already.
+// It's meant for observations vocabulary: sequence to be decoded could repeat observations, so AddObservation has to be used for it
+func (v *Viterbi) AddObservationChecked(obs Observation) error {
+	if isNil(obs) {
+		return ErrNilObservation
+	}
+	if prev, ok := v.ObservationByID(obs.ID()); ok {
+		return fmt.Errorf("%w: %v has the same ID %d as %v", ErrDuplicateObservation, obs, obs.ID(), prev)
+	}
+	v.AddObservation(obs)
+	return nil
+}
+
+// ObservationByID returns the first added observation with given ID() and whether it has been found
+func (v *Viterbi) ObservationByID(id int) (Observation, bool) {
+	for _, obs := range v.observations {
+		if obs.ID() == id {
+			return obs, true
+		}
+	}
+	return nil, false
+}
+
+// RemoveState removes state from model together with every start, emission and transition probability referencing it.
+// Removing state which has not been added is no-op
+func (m *Model) RemoveState(s State) {
+	id := s.ID()
+	states := m.states[:0]
+	for _, st := range m.states {
+		if st.ID() != id {
+			states = append(states, st)
+		}
+	}
+	m.states = states
+	delete(m.startProbabilities, id)
+	delete(m.endProbabilities, id)
+	for key := range m.emissionProbabilities {
+		if key.State == id {
+			delete(m.emissionProbabilities, key)
+		}
+	}
+	for key := range m.transitionProbabilities {
+		if key.From == id || key.To == id {
+			delete(m.transitionProbabilities, key)
+		}
+	}
+	for key := range m.transitionProbabilities2 {
+		if key.PrevPrev == id || key.Prev == id || key.To == id {
+			delete(m.transitionProbabilities2, key)
+		}
+	}
+	for key := range m.forbiddenTransitions {
+		if key.From == id || key.To == id {
+			delete(m.forbiddenTransitions, key)
+		}
+	}
+	for key := range m.durationProbabilities {
+		if key.State == id {
+			delete(m.durationProbabilities, key)
+		}
+	}
+	delete(m.predecessors, id)
+	for to, preds := range m.predecessors {
+		kept := preds[:0]
+		for _, st := range preds {
+			if st.ID() != id {
+				kept = append(kept, st)
+			}
+		}
+		m.predecessors[to] = kept
 	}
-	v.startProbabilities[state] = val
 }
 
-func (v *Viterbi) PutEmissionProbability(s State, obs Observation, val float64) {
-	if v.emissionProbabilities == nil {
-		v.emissionProbabilities = make(map[EmissionHash]float64)
+// RemoveObservation removes observation from model together with every emission probability referencing it.
+// Removing observation which has not been added is no-op
+func (v *Viterbi) RemoveObservation(obs Observation) {
+	id := obs.ID()
+	observations := v.observations[:0]
+	for _, o := range v.observations {
+		if o.ID() != id {
+			observations = append(observations, o)
+		}
 	}
-	emKey := EmissionHash{s, obs}
-	if _, ok := v.emissionProbabilities[emKey]; !ok {
-		v.emissionProbabilities[emKey] = val
+	v.observations = observations
+	for key := range v.emissionProbabilities {
+		if key.observation == id {
+			delete(v.emissionProbabilities, key)
+		}
 	}
 }
 
-func (v *Viterbi) PutTransitionProbability(f State, t State, val float64) {
-	if v.transitionProbabilities == nil {
-		v.transitionProbabilities = make(map[TransitionHash]float64)
+// Reset clears states, observations, every probability (including default and computed ones together with their weights)
+// and counts accumulated by Inc* methods, so instance could be reused for another problem.
+// Evaluation settings (beam width, tie breaker, orientation, tolerance and so on) and already allocated memory are kept
+func (v *Viterbi) Reset() {
+	v.states = v.states[:0]
+	for st := range v.startProbabilities {
+		delete(v.startProbabilities, st)
 	}
-	trKey := TransitionHash{f, t}
-	if _, ok := v.transitionProbabilities[trKey]; !ok {
-		v.transitionProbabilities[trKey] = val
+	for st := range v.endProbabilities {
+		delete(v.endProbabilities, st)
 	}
+	for key := range v.transitionProbabilities {
+		delete(v.transitionProbabilities, key)
+	}
+	for key := range v.transitionProbabilities2 {
+		delete(v.transitionProbabilities2, key)
+	}
+	for key := range v.durationProbabilities {
+		delete(v.durationProbabilities, key)
+	}
+	for st := range v.startCounts {
+		delete(v.startCounts, st)
+	}
+	for key := range v.emissionCounts {
+		delete(v.emissionCounts, key)
+	}
+	for key := range v.transitionCounts {
+		delete(v.transitionCounts, key)
+	}
+	v.emissionFunc, v.transitionFunc = nil, nil
+	v.unknownEmission, v.defaultEmission, v.defaultTransition = nil, nil, nil
+	v.emissionWeight, v.transitionWeight = nil, nil
+	v.predecessors = nil
+	v.forbiddenTransitions = nil
+	v.overwrites = nil
+	v.ResetObservations()
+}
+
+// ResetObservations clears observations, their constraints and emission probabilities (including time-indexed ones and missing marks) only.
+// States, start and transition probabilities are kept, so instance could be reused for decoding another observations sequence
+func (v *Viterbi) ResetObservations() {
+	v.observations = v.observations[:0]
+	v.stream = nil
+	v.constraints = nil
+	v.timedEmissions = nil
+	v.noEmissions = nil
+	for key := range v.emissionProbabilities {
+		delete(v.emissionProbabilities, key)
+	}
+}
+
+func (m *Model) PutStartProbability(state State, val float64) {
+	mustState(state, "PutStartProbability")
+	if m.startProbabilities == nil {
+		m.startProbabilities = make(map[int]float64)
+	}
+	prev, exists := m.startProbabilities[state.ID()]
+	m.recordOverwrite(prev, exists, val, "start probability of state %v", state)
+	m.startProbabilities[state.ID()] = val
+}
+
+func (m *Model) PutEmissionProbability(s State, obs Observation, val float64) {
+	mustState(s, "PutEmissionProbability")
+	mustObservation(obs, "PutEmissionProbability")
+	if m.emissionProbabilities == nil {
+		m.emissionProbabilities = make(map[EmissionHash]float64)
+	}
+	emKey := EmissionHash{s.ID(), obs.ID()}
+	prev, exists := m.emissionProbabilities[emKey]
+	m.recordOverwrite(prev, exists, val, "emission probability of state %v for observation %v", s, obs)
+	if !exists {
+		m.emissionProbabilities[emKey] = val
+	}
+}
+
+// PutEmissionProbabilityChecked is the same as PutEmissionProbability, but returns ErrUnknownState when state has not been added
+// and ErrUnknownObservation when observation has not been added (both are identified by plain ID(), so swapped arguments are caught this way)
+func (v *Viterbi) PutEmissionProbabilityChecked(s State, obs Observation, val float64) error {
+	if isNil(s) {
+		return ErrNilState
+	}
+	if isNil(obs) {
+		return ErrNilObservation
+	}
+	if _, ok := v.stateWithID(s.ID()); !ok {
+		return fmt.Errorf("%w: %v with ID %d", ErrUnknownState, s, s.ID())
+	}
+	if _, ok := v.ObservationByID(obs.ID()); !ok {
+		return fmt.Errorf("%w: %v with ID %d", ErrUnknownObservation, obs, obs.ID())
+	}
+	v.PutEmissionProbability(s, obs, val)
+	return nil
+}
+
+func (m *Model) PutTransitionProbability(f State, t State, val float64) {
+	mustState(f, "PutTransitionProbability")
+	mustState(t, "PutTransitionProbability")
+	if m.transitionProbabilities == nil {
+		m.transitionProbabilities = make(map[TransitionHash]float64)
+	}
+	trKey := TransitionHash{f.ID(), t.ID()}
+	prev, exists := m.transitionProbabilities[trKey]
+	m.recordOverwrite(prev, exists, val, "transition probability from state %v to state %v", f, t)
+	if !exists {
+		m.transitionProbabilities[trKey] = val
+	}
+}
+
+// AddStartProbability adds delta to start probability of the state (missing one is treated as zero), e.g. for counting before Normalize
+func (m *Model) AddStartProbability(state State, delta float64) {
+	mustState(state, "AddStartProbability")
+	if m.startProbabilities == nil {
+		m.startProbabilities = make(map[int]float64)
+	}
+	m.startProbabilities[state.ID()] += delta
+}
+
+// AddEmissionProbability adds delta to probability of the state to emit observation (missing one is treated as zero)
+func (m *Model) AddEmissionProbability(s State, obs Observation, delta float64) {
+	mustState(s, "AddEmissionProbability")
+	mustObservation(obs, "AddEmissionProbability")
+	if m.emissionProbabilities == nil {
+		m.emissionProbabilities = make(map[EmissionHash]float64)
+	}
+	m.emissionProbabilities[EmissionHash{s.ID(), obs.ID()}] += delta
+}
+
+// AddTransitionProbability adds delta to probability of transition between states (missing one is treated as zero)
+func (m *Model) AddTransitionProbability(f State, t State, delta float64) {
+	mustState(f, "AddTransitionProbability")
+	mustState(t, "AddTransitionProbability")
+	if m.transitionProbabilities == nil {
+		m.transitionProbabilities = make(map[TransitionHash]float64)
+	}
+	m.transitionProbabilities[TransitionHash{f.ID(), t.ID()}] += delta
+}
+
+// PutEmissionProbabilityAt puts probability of the state to emit observation with index t of the sequence.
+// Time-indexed emission is preferred over emission put via PutEmissionProbability for the same state and observation.
+// Such probabilities belong to the current observations sequence and are cleared by ResetObservations
+func (v *Viterbi) PutEmissionProbabilityAt(s State, t int, val float64) {
+	mustState(s, "PutEmissionProbabilityAt")
+	if v.timedEmissions == nil {
+		v.timedEmissions = make(map[TimedEmissionHash]float64)
+	}
+	emKey := TimedEmissionHash{s.ID(), t}
+	if _, ok := v.timedEmissions[emKey]; !ok {
+		v.timedEmissions[emKey] = val
+	}
+}
+
+// PutNoEmissionAt marks observation with index t of the sequence as missing: every state emits it with probability of one,
+// so only start (transition) probabilities contribute to that timestep. Constraints of the timestep are still applied.
+// Such marks belong to the current observations sequence and are cleared by ResetObservations
+func (v *Viterbi) PutNoEmissionAt(t int) {
+	if v.noEmissions == nil {
+		v.noEmissions = make(map[int]struct{})
+	}
+	v.noEmissions[t] = struct{}{}
+}
+
+// PutEndProbability puts probability of the path to end in the state (termination probability).
+// Evaluators multiply probabilities of the last observation by it; states without end probability have one
+func (m *Model) PutEndProbability(state State, val float64) {
+	mustState(state, "PutEndProbability")
+	if m.endProbabilities == nil {
+		m.endProbabilities = make(map[int]float64)
+	}
+	prev, exists := m.endProbabilities[state.ID()]
+	m.recordOverwrite(prev, exists, val, "end probability of state %v", state)
+	m.endProbabilities[state.ID()] = val
+}
+
+// States returns copy of added states in order they have been added
+func (m *Model) States() []State {
+	return append([]State{}, m.states...)
+}
+
+// Observations returns copy of added observations in order they have been added
+func (v *Viterbi) Observations() []Observation {
+	return append([]Observation{}, v.observations...)
+}
+
+// GetStartProbability returns start probability of the state and whether it has been set
+func (m *Model) GetStartProbability(s State) (float64, bool) {
+	val, ok := m.startProbabilities[s.ID()]
+	return val, ok
+}
+
+// GetEndProbability returns end probability of the state and whether it has been set
+func (m *Model) GetEndProbability(s State) (float64, bool) {
+	val, ok := m.endProbabilities[s.ID()]
+	return val, ok
+}
+
+// GetEmissionProbability returns probability of the state to emit observation and whether it has been set
+func (m *Model) GetEmissionProbability(s State, obs Observation) (float64, bool) {
+	val, ok := m.emissionProbabilities[EmissionHash{s.ID(), obs.ID()}]
+	return val, ok
+}
+
+// GetTransitionProbability returns probability of transition between states and whether it has been set
+func (m *Model) GetTransitionProbability(from State, to State) (float64, bool) {
+	val, ok := m.transitionProbabilities[TransitionHash{from.ID(), to.ID()}]
+	return val, ok
 }
 
 // EvalPath see ref bellow
 // https://en.wikipedia.org/wiki/Viterbi_algorithm#Pseudocode
 // When every probability is in [0;1]
-func (v Viterbi) EvalPath() ViterbiPath {
+// Equal probabilities are resolved according to the tie-breaker (see SetTieBreaker), in favour of the state with the lowest ID() by default
+func (v *Viterbi) EvalPath() (ViterbiPath, error) {
+	return v.evalPath(false)
+}
+
+// EvalPathLogProbabilities When every probability is logarithmic
+// Equal probabilities are resolved according to the tie-breaker (see SetTieBreaker), in favour of the state with the lowest ID() by default
+func (v *Viterbi) EvalPathLogProbabilities() (ViterbiPath, error) {
+	return v.evalPath(true)
+}
+
+// EvalPathContext is the same as EvalPath, but evaluation is aborted with ctx.Err() when context is done
+func (v *Viterbi) EvalPathContext(ctx context.Context) (ViterbiPath, error) {
+	return v.evalPathContext(ctx, false)
+}
+
+// EvalPathLogProbabilitiesContext is the same as EvalPathLogProbabilities, but evaluation is aborted with ctx.Err() when context is done
+func (v *Viterbi) EvalPathLogProbabilitiesContext(ctx context.Context) (ViterbiPath, error) {
+	return v.evalPathContext(ctx, true)
+}
+
+func (v *Viterbi) evalPath(logSpace bool) (ViterbiPath, error) {
+	return v.evalPathContext(context.Background(), logSpace)
+}
+
+func (v *Viterbi) evalPathContext(ctx context.Context, logSpace bool) (ViterbiPath, error) {
+	V, logScale, err := v.evalTrellis(ctx, evalOptions{logSpace: logSpace})
+	if err != nil {
+		return ViterbiPath{}, err
+	}
+	return v.backtrack(V, logSpace, logScale)
+}
+
+// evalOptions are settings of single evaluation call
+type evalOptions struct {
+	// logSpace is true when every probability is logarithmic
+	logSpace bool
+	// workers is number of goroutines evaluating trellis column. Column is evaluated sequentially when it's less than 2
+	workers int
+	// stats collects statistics of evaluation (when not nil)
+	stats *DecodeStats
+	// index is dense index of every state ID (see stateIndex). It's built by evalColumn when nil
+	index map[int]int
+	// logScales receives logarithm of scaling factor accumulated up to every column, inclusive (when not nil, see rescaleColumn)
+	logScales []float64
+}
+
+// evalTrellis evaluates trellis of the most probable partial paths: V[t][s] is probability of the best path ending in state s at observation t.
+// Second return value is logarithm of accumulated scaling factor of classic probabilities (see rescaleColumn).
+// When path is broken columns of preceding observations are returned alongside *PathBrokenError
+func (v *Viterbi) evalTrellis(ctx context.Context, opts evalOptions) ([]map[State]ViterbiVal, float64, error) {
+	if err := v.validate(); err != nil {
+		return nil, 0, err
+	}
+	if err := ctx.Err(); err != nil {
+		return nil, 0, err
+	}
+
+	opts.index = v.stateIndex()
+	V := make([]map[State]ViterbiVal, len(v.observations))
+	column, err := v.evalInitialColumn(opts)
+	if err != nil {
+		return nil, 0, err
+	}
+	if len(column) == 0 {
+		return nil, 0, v.pathBroken(0)
+	}
+	V[0] = column
+	opts.stats.addColumn(column)
+	logScale := 0.0
+	if !opts.logSpace {
+		logScale += v.rescaleColumn(column)
+	}
+	if opts.logScales != nil {
+		opts.logScales[0] = logScale
+	}
+	if err := v.checkMinProbability(column, 0, opts.logSpace, logScale); err != nil {
+		return nil, 0, err
+	}
+
+	for t := 1; t < len(v.observations); t++ {
+		if err := ctx.Err(); err != nil {
+			return nil, 0, err
+		}
+		column, err := v.evalColumn(V[t-1], t, opts)
+		if err != nil {
+			return nil, 0, err
+		}
+		if len(column) == 0 {
+			// Columns evaluated so far are kept for EvalPathPartial
+			return V[:t], logScale, v.pathBroken(t)
+		}
+		if !opts.logSpace {
+			logScale += v.rescaleColumn(column)
+		}
+		if opts.logScales != nil {
+			opts.logScales[t] = logScale
+		}
+		if err := v.checkMinProbability(column, t, opts.logSpace, logScale); err != nil {
+			return nil, 0, err
+		}
+		V[t] = column
+		opts.stats.addColumn(column)
+	}
+
+	return V, logScale, nil
+}
+
+// evalInitialColumn evaluates trellis column for the first observation
+func (v *Viterbi) evalInitialColumn(opts evalOptions) (map[State]ViterbiVal, error) {
+	column := v.newColumn()
+	unknown := v.unknownAt(0)
+	for _, st := range v.states {
+		prob, ok, err := v.initialProbability(st, unknown, opts.logSpace)
+		if err != nil {
+			return nil, err
+		}
+		if !ok {
+			continue
+		}
+		column[st] = ViterbiVal{prob: prob}
+	}
+	opts.stats.addPruned(0, v.pruneColumn(column, opts.logSpace))
+	return column, nil
+}
+
+// evalColumn evaluates trellis column for observation t given column of previous observation
+func (v *Viterbi) evalColumn(prev map[State]ViterbiVal, t int, opts evalOptions) (map[State]ViterbiVal, error) {
+	if opts.index == nil {
+		opts.index = v.stateIndex()
+	}
 	var (
-		V     []map[State]ViterbiVal
-		path  map[State][]State
-		maxPr = -math.MaxFloat64
+		column map[State]ViterbiVal
+		err    error
 	)
+	dense := v.toDense(prev)
+	unknown := v.unknownAt(t)
+	if opts.workers > 1 {
+		column, err = v.evalColumnParallel(dense, t, unknown, opts)
+	} else {
+		column, err = v.evalColumnSequential(dense, t, unknown, opts)
+	}
+	if err != nil {
+		return nil, err
+	}
+	opts.stats.addPruned(t, v.pruneColumn(column, opts.logSpace))
+	return column, nil
+}
 
-	V = append(V, make(map[State]ViterbiVal))
-	path = make(map[State][]State)
-
-	for s := range v.states {
-		st := v.states[s]
-		if _, ok := v.startProbabilities[st]; !ok {
+// evalColumnSequential evaluates every cell of trellis column one by one
+func (v *Viterbi) evalColumnSequential(prev denseColumn, t int, unknown bool, opts evalOptions) (map[State]ViterbiVal, error) {
+	column := v.newColumn()
+	for _, s := range v.states {
+		value, ok, err := v.evalCell(prev, s, t, unknown, opts)
+		if err != nil {
+			return nil, err
+		}
+		if !ok {
 			continue
 		}
-		V[0][st] = ViterbiVal{prob: v.startProbabilities[st] * v.emissionProbabilities[EmissionHash{st, v.observations[0]}]}
-		path[st] = append(path[st], st)
+		column[s] = value
 	}
+	return column, nil
+}
 
-	for t := 1; t < len(v.observations); t++ {
-		V = append(V, make(map[State]ViterbiVal))
-		for s1 := range v.states {
-			s := v.states[s1]
-			if _, ok := v.emissionProbabilities[EmissionHash{s, v.observations[t]}]; !ok {
-				// No emission for current state of current observation
+// evalCell evaluates the most probable path ending in state s at observation t (unknown is v.unknownAt(t) evaluated once per column).
+// Second return value is false when state is unreachable at observation t
+func (v *Viterbi) evalCell(prev denseColumn, s State, t int, unknown bool, opts evalOptions) (ViterbiVal, bool, error) {
+	logSpace := opts.logSpace
+	emissionProb, ok, err := v.emissionProbability(s, t, unknown, logSpace)
+	if err != nil {
+		return ViterbiVal{}, false, err
+	}
+	if !ok {
+		// No emission for current state of current observation
+		return ViterbiVal{}, false, nil
+	}
+	best := ViterbiVal{}
+	preds, restricted := v.predecessors[s.ID()]
+	n := len(v.states)
+	if restricted {
+		n = len(preds)
+	}
+	for k := 0; k < n; k++ {
+		j := k
+		if restricted {
+			if j, ok = opts.index[preds[k].ID()]; !ok {
 				continue
 			}
-			maxTransitionProbability := -math.MaxFloat64
-			tmpState := v.states[0]
-			metFirst := false
-			for s2 := range v.states {
-				r := v.states[s2]
-				vTransition, ok := v.transitionProbabilities[TransitionHash{r, s}]
-				if !ok {
-					// No transition between states
-					continue
-				}
-				stateProb, ok := V[t-1][r]
-				if !ok {
-					// No probability from state to observation
-					continue
-				}
-				if !metFirst {
-					metFirst = true
-					tmpState = r
-				}
-				transitionProbability := vTransition
-				if vTransition > -math.MaxFloat64 {
-					transitionProbability *= stateProb.prob
-				}
-				if transitionProbability > maxTransitionProbability {
-					maxTransitionProbability = transitionProbability
-					tmpState = r
-				}
-			}
-			maxProbability := maxTransitionProbability
-			if maxProbability > -math.MaxFloat64 {
-				maxProbability *= v.emissionProbabilities[EmissionHash{s, v.observations[t]}]
-			}
-			V[t][s] = ViterbiVal{prob: maxProbability, prev: tmpState}
 		}
+		if !prev.set[j] {
+			// No probability from state to observation
+			continue
+		}
+		r, stateProb := v.states[j], prev.values[j]
+		transitionProb, ok, err := v.transitionProbability(r, s, logSpace)
+		if err != nil {
+			return ViterbiVal{}, false, err
+		}
+		if !ok {
+			// No transition between states
+			continue
+		}
+		prob := combine(logSpace, stateProb.prob, transitionProb)
+		if best.prev == nil || v.preferState(prob, r, best.prob, best.prev) {
+			best = ViterbiVal{prob: prob, prev: r}
+		}
+	}
+	if best.prev == nil {
+		// State is unreachable from any state of previous observation
+		return ViterbiVal{}, false, nil
 	}
+	prob := combine(logSpace, best.prob, emissionProb)
+	if impossible(logSpace, prob) {
+		return ViterbiVal{}, false, nil
+	}
+	return ViterbiVal{prob: prob, prev: best.prev}, true, nil
+}
 
-	for _, value := range V[len(V)-1] {
-		if value.prob > maxPr {
-			maxPr = value.prob
+// backtrack restores the most probable path from trellis. logScale is logarithm of scaling factor of classic probabilities.
+// ErrNoValidPath is returned when the best path has zero probability
+func (v *Viterbi) backtrack(V []map[State]ViterbiVal, logSpace bool, logScale float64) (ViterbiPath, error) {
+	final := make(map[State]float64, len(V[len(V)-1]))
+	for st, value := range V[len(V)-1] {
+		endProb, err := v.endProbability(st, logSpace)
+		if err != nil {
+			return ViterbiPath{}, err
 		}
+		final[st] = combine(logSpace, value.prob, endProb)
 	}
 
-	opt := []State{}
 	var previous State
-	for st, value := range V[len(V)-1] {
-		if value.prob == maxPr {
-			opt = append(opt, st)
-			previous = st
-			break
+	maxPr := math.Inf(-1)
+	for st, prob := range final {
+		if previous == nil || v.preferState(prob, st, maxPr, previous) {
+			previous, maxPr = st, prob
 		}
 	}
+	if previous == nil || impossible(logSpace, maxPr) {
+		return ViterbiPath{}, ErrNoValidPath
+	}
+	// Path is filled from the end, so no prepending is needed
+	opt := make([]State, len(V))
+	opt[len(V)-1] = previous
 	for t := len(V) - 2; t >= 0; t-- {
-		opt = append([]State{V[t+1][previous].prev}, opt...)
 		previous = V[t+1][previous].prev
+		opt[t] = previous
 	}
 
-	return ViterbiPath{maxPr, opt}
+	var ambiguous []bool
+	if v.ambiguityEpsilon != nil {
+		ambiguous = v.ambiguousSteps(V, final, opt, logSpace)
+	}
+	if logSpace {
+		return ViterbiPath{Probability: maxPr, LogProbability: maxPr, Path: opt, StepProbabilities: v.stepProbabilities(opt, logSpace), Ambiguous: ambiguous}, nil
+	}
+	return ViterbiPath{Probability: maxPr * math.Exp(logScale), LogProbability: math.Log(maxPr) + logScale, Path: opt, StepProbabilities: v.stepProbabilities(opt, logSpace), Ambiguous: ambiguous}, nil
 }
 
-// EvalPathLogProbabilities When every probability is logarithmic
-func (v Viterbi) EvalPathLogProbabilities() ViterbiPath {
-	var (
-		V     []map[State]ViterbiVal
-		path  map[State][]State
-		maxPr = -math.MaxFloat64
-	)
+// validate checks that model is ready for evaluation
+func (v *Viterbi) validate() error {
+	if len(v.states) == 0 {
+		return ErrNoStates
+	}
+	if len(v.observations) == 0 {
+		return ErrNoObservations
+	}
+	if v.overwritePolicy == OverwriteError && len(v.overwrites) != 0 {
+		return &ValidationError{Problems: v.Overwrites()}
+	}
+	return nil
+}
+
+// initialProbability returns start probability of the state combined with its emission for the first observation.
+// Second return value is false when state can't start the path. Unknown is v.unknownAt(0)
+func (v *Viterbi) initialProbability(st State, unknown bool, logSpace bool) (float64, bool, error) {
+	startProb, ok, err := v.startProbability(st, logSpace)
+	if err != nil || !ok {
+		return 0, false, err
+	}
+	emissionProb, ok, err := v.emissionProbability(st, 0, unknown, logSpace)
+	if err != nil || !ok {
+		return 0, false, err
+	}
+	prob := combine(logSpace, startProb, emissionProb)
+	if impossible(logSpace, prob) {
+		return 0, false, nil
+	}
+	return prob, true, nil
+}
 
-	V = append(V, make(map[State]ViterbiVal))
-	path = make(map[State][]State)
+// startProbability returns start probability of the state. Second return value is false when state can't start the path
+func (v *Viterbi) startProbability(st State, logSpace bool) (float64, bool, error) {
+	startProb, ok := v.startProbabilities[st.ID()]
+	if !ok {
+		return 0, false, nil
+	}
+	startProb, ok = v.clampProbability(logSpace, startProb)
+	if !ok {
+		return 0, false, fmt.Errorf("%w: start probability %v of state %v", ErrInvalidProbability, startProb, st)
+	}
+	return startProb, true, nil
+}
 
-	for s := range v.states {
-		st := v.states[s]
-		if _, ok := v.startProbabilities[st]; !ok {
-			continue
-		}
-		V[0][st] = ViterbiVal{prob: v.startProbabilities[st] + v.emissionProbabilities[EmissionHash{st, v.observations[0]}]}
-		path[st] = append(path[st], st)
+// endProbability returns probability of the path to end in the state. It's one for states without end probability
+func (v *Viterbi) endProbability(s State, logSpace bool) (float64, error) {
+	endProb, ok := v.endProbabilities[s.ID()]
+	if !ok {
+		return one(logSpace), nil
+	}
+	endProb, ok = v.clampProbability(logSpace, endProb)
+	if !ok {
+		return 0, fmt.Errorf("%w: end probability %v of state %v", ErrInvalidProbability, endProb, s)
 	}
+	return endProb, nil
+}
 
-	for t := 1; t < len(v.observations); t++ {
-		V = append(V, make(map[State]ViterbiVal))
-		for s1 := range v.states {
-			s := v.states[s1]
-			if _, ok := v.emissionProbabilities[EmissionHash{s, v.observations[t]}]; !ok {
-				// No emission for current state of current observation
-				continue
-			}
-			maxTransitionProbability := -math.MaxFloat64
-			tmpState := v.states[0]
-			metFirst := false
-			for s2 := range v.states {
-				r := v.states[s2]
-				vTransition, ok := v.transitionProbabilities[TransitionHash{r, s}]
-				if !ok {
-					// No transition between states
-					continue
-				}
-				stateProb, ok := V[t-1][r]
-				if !ok {
-					// No probability from state to observation
-					continue
-				}
-				if !metFirst {
-					metFirst = true
-					tmpState = r
-				}
-				transitionProbability := vTransition
-				if vTransition > -math.MaxFloat64 {
-					transitionProbability += stateProb.prob
-				}
-				if transitionProbability > maxTransitionProbability {
-					maxTransitionProbability = transitionProbability
-					tmpState = r
-				}
-			}
-			maxProbability := maxTransitionProbability
-			if maxProbability > -math.MaxFloat64 {
-				maxProbability += v.emissionProbabilities[EmissionHash{s, v.observations[t]}]
+// emissionProbability returns probability of the state to emit observation with index t.
+// Unknown is v.unknownAt(t): it does not depend on the state, so callers evaluate it once per observation
+func (v *Viterbi) emissionProbability(s State, t int, unknown bool, logSpace bool) (float64, bool, error) {
+	emissionProb, ok, none := v.lookupEmission(s, t, unknown)
+	if none {
+		return one(logSpace), true, nil
+	}
+	if !ok {
+		return 0, false, nil
+	}
+	emissionProb, ok = v.clampEmission(logSpace, emissionProb)
+	if !ok {
+		return 0, false, fmt.Errorf("%w: emission probability %v of state %v for observation %v", ErrInvalidProbability, emissionProb, s, v.observations[t])
+	}
+	return weigh(logSpace, emissionProb, v.emissionWeight), true, nil
+}
+
+// lookupEmission returns emission probability of the state for observation with index t as is: without range check and weight.
+// Third return value is true when observation has no emission at all (see PutNoEmissionAt)
+func (v *Viterbi) lookupEmission(s State, t int, unknown bool) (float64, bool, bool) {
+	if !v.allowed(s, t) {
+		return 0, false, false
+	}
+	if _, ok := v.noEmissions[t]; ok {
+		return 0, false, true
+	}
+	emissionProb, ok := v.timedEmissions[TimedEmissionHash{s.ID(), t}]
+	if !ok {
+		emissionProb, ok = v.emissionProbabilities[EmissionHash{s.ID(), v.observations[t].ID()}]
+	}
+	if !ok && v.emissionFunc != nil {
+		emissionProb, ok = v.emissionFunc(s, v.observations[t]), true
+	}
+	if !ok && unknown {
+		emissionProb, ok = *v.unknownEmission, true
+	}
+	if !ok && v.defaultEmission != nil {
+		emissionProb, ok = *v.defaultEmission, true
+	}
+	return emissionProb, ok, false
+}
+
+// transitionProbability returns probability of transition between two states
+func (v *Viterbi) transitionProbability(from, to State, logSpace bool) (float64, bool, error) {
+	transitionProb, ok, forbidden, err := v.lookupTransition(from, to)
+	if err != nil {
+		return 0, false, err
+	}
+	if forbidden {
+		return zero(logSpace), true, nil
+	}
+	if !ok {
+		return 0, false, nil
+	}
+	transitionProb, ok = v.clampProbability(logSpace, transitionProb)
+	if !ok {
+		return 0, false, fmt.Errorf("%w: transition probability %v from state %v to state %v", ErrInvalidProbability, transitionProb, from, to)
+	}
+	return weigh(logSpace, transitionProb, v.transitionWeight), true, nil
+}
+
+// lookupTransition returns transition probability between two states as is: without range check and weight.
+// Third return value is true when transition is forbidden (see ForbidTransition)
+func (v *Viterbi) lookupTransition(from, to State) (float64, bool, bool, error) {
+	if _, ok := v.forbiddenTransitions[TransitionHash{from.ID(), to.ID()}]; ok {
+		return 0, false, true, nil
+	}
+	transitionProb, ok := v.transitionProbabilities[v.transitionKey(from, to)]
+	if !ok && v.transitionFunc != nil {
+		transitionProb, ok = v.transitionFunc(from, to)
+		if !ok {
+			return 0, false, false, nil
+		}
+	}
+	if !ok {
+		if v.defaultTransition == nil {
+			if v.strictTransitions {
+				return 0, false, false, fmt.Errorf("%w: from state %v to state %v", ErrMissingTransition, from, to)
 			}
-			V[t][s] = ViterbiVal{prob: maxProbability, prev: tmpState}
+			return 0, false, false, nil
 		}
+		transitionProb, ok = *v.defaultTransition, true
 	}
+	return transitionProb, ok, false, nil
+}
 
-	for _, value := range V[len(V)-1] {
-		if value.prob > maxPr {
-			maxPr = value.prob
-		}
+// combine extends path probability: product for classic probabilities and sum for logarithmic ones
+func combine(logSpace bool, a, b float64) float64 {
+	if logSpace {
+		return a + b
 	}
+	return a * b
+}
 
-	opt := []State{}
-	var previous State
-	for st, value := range V[len(V)-1] {
-		if value.prob == maxPr {
-			opt = append(opt, st)
-			previous = st
-			break
-		}
+// weigh raises classic probability to the power of weight (multiplies logarithmic one by it). Probability is unchanged when weight is not set
+func weigh(logSpace bool, val float64, weight *float64) float64 {
+	if weight == nil {
+		return val
 	}
-	for t := len(V) - 2; t >= 0; t-- {
-		opt = append([]State{V[t+1][previous].prev}, opt...)
-		previous = V[t+1][previous].prev
+	if logSpace {
+		return logPower(val, *weight)
 	}
+	return math.Pow(val, *weight)
+}
 
-	return ViterbiPath{maxPr, opt}
+// logPower raises logarithmic probability to the power: a·w
+func logPower(a, w float64) float64 {
+	if w == 0 {
+		// Product would be NaN for impossible (-Inf) probability, while classic one is raised to 1
+		return 0
+	}
+	return a * w
 }
 
-func printPathTable(V []map[State]ViterbiVal) {
-	fmt.Printf("    ")
-	for i := 0; i < len(V); i++ {
-		fmt.Printf("%7d ", i)
+// validProbability checks range of classic probabilities. Logarithmic ones are not restricted, but NaN is invalid for both
+func validProbability(logSpace bool, val float64) bool {
+	if logSpace {
+		return !math.IsNaN(val)
 	}
-	fmt.Println("")
+	return val >= 0 && val <= 1
+}
 
-	for y := range V[0] {
-		fmt.Printf("%.5s:  ", y)
-		for t := 0; t < len(V); t++ {
-			fmt.Printf("%.5f ", V[t][y].prob)
+// clampProbability checks range of probability with respect to tolerance of the model and clamps classic probability into [0;1] range.
+// Second return value is false when probability is invalid
+func (m *Model) clampProbability(logSpace bool, val float64) (float64, bool) {
+	if !logSpace && m.tolerance > 0 {
+		if val < 0 && val >= -m.tolerance {
+			val = 0
+		} else if val > 1 && val <= 1+m.tolerance {
+			val = 1
 		}
-		fmt.Println("")
 	}
+	return val, validProbability(logSpace, val)
+}
+
+// clampEmission is the same as clampProbability, but classic emission above 1 is valid (and left as is) when emissions are densities
+// (see SetEmissionsAreDensities)
+func (m *Model) clampEmission(logSpace bool, val float64) (float64, bool) {
+	if m.emissionDensities && !logSpace && val > 1 {
+		return val, !math.IsInf(val, 1)
+	}
+	return m.clampProbability(logSpace, val)
+}
+
+// impossible reports whether probability can't be reached at all: zero for classic probability, -Inf for logarithmic one.
+// Such states are skipped the same way as missing ones
+func impossible(logSpace bool, val float64) bool {
+	if logSpace {
+		return math.IsInf(val, -1)
+	}
+	return val == 0
+}
+
+// zero returns probability of impossible event: 0 for classic probabilities and -Inf for logarithmic ones
+func zero(logSpace bool) float64 {
+	if logSpace {
+		return math.Inf(-1)
+	}
+	return 0
+}
+
+// preferState reports whether state a with probability pa has to be chosen over state b with probability pb.
+// Equal probabilities are resolved by tie-breaker of the model (see SetTieBreaker), in favour of the state with the lowest ID() by default
+func (m *Model) preferState(pa float64, a State, pb float64, b State) bool {
+	if pa != pb || m.tieBreaker == nil {
+		return preferLowestID(pa, a, pb, b)
+	}
+	return m.tieBreaker(a, b)
+}
+
+// preferLowestID is the same as preferState, but equal probabilities are always resolved in favour of the state with the lowest ID()
+func preferLowestID(pa float64, a State, pb float64, b State) bool {
+	if pa != pb {
+		return pa > pb
+	}
+	return a.ID() < b.ID()
 }
@@ -0,0 +1,241 @@
+package viterbi
+
+import (
+	"fmt"
+	"math"
+	"sort"
+)
+
+// Viterbi32 is variant of Viterbi which stores probabilities as float32, so memory footprint of large models
+// is roughly halved at the cost of precision. Trellis keeps back-pointers of reachable cells only, together with float32 probabilities of two columns.
+// Classic probabilities of trellis are rescaled for every observation, so they don't underflow.
+// States and observations are identified by ID() the same way as in Viterbi
+type Viterbi32 struct {
+	states                  []State
+	observations            []Observation
+	startProbabilities      map[int]float32
+	emissionProbabilities   map[EmissionHash]float32
+	transitionProbabilities map[TransitionHash]float32
+}
+
+// New32 returns empty Viterbi32
+func New32() *Viterbi32 {
+	return &Viterbi32{
+		startProbabilities:      make(map[int]float32),
+		emissionProbabilities:   make(map[EmissionHash]float32),
+		transitionProbabilities: make(map[TransitionHash]float32),
+	}
+}
+
+func (v *Viterbi32) AddState(s State) {
+	mustState(s, "AddState")
+	v.states = append(v.states, s)
+}
+
+func (v *Viterbi32) AddObservation(obs Observation) {
+	mustObservation(obs, "AddObservation")
+	v.observations = append(v.observations, obs)
+}
+
+func (v *Viterbi32) PutStartProbability(state State, val float32) {
+	mustState(state, "PutStartProbability")
+	if v.startProbabilities == nil {
+		v.startProbabilities = make(map[int]float32)
+	}
+	v.startProbabilities[state.ID()] = val
+}
+
+func (v *Viterbi32) PutEmissionProbability(s State, obs Observation, val float32) {
+	mustState(s, "PutEmissionProbability")
+	mustObservation(obs, "PutEmissionProbability")
+	if v.emissionProbabilities == nil {
+		v.emissionProbabilities = make(map[EmissionHash]float32)
+	}
+	emKey := EmissionHash{s.ID(), obs.ID()}
+	if _, ok := v.emissionProbabilities[emKey]; !ok {
+		v.emissionProbabilities[emKey] = val
+	}
+}
+
+func (v *Viterbi32) PutTransitionProbability(f State, t State, val float32) {
+	mustState(f, "PutTransitionProbability")
+	mustState(t, "PutTransitionProbability")
+	if v.transitionProbabilities == nil {
+		v.transitionProbabilities = make(map[TransitionHash]float32)
+	}
+	trKey := TransitionHash{f.ID(), t.ID()}
+	if _, ok := v.transitionProbabilities[trKey]; !ok {
+		v.transitionProbabilities[trKey] = val
+	}
+}
+
+// EvalPath is the same as Viterbi.EvalPath, but evaluated in float32.
+// Probabilities of returned path are float64, but precision of them is float32 one
+// When every probability is in [0;1]
+// Equal probabilities are resolved in favour of the state with the lowest ID()
+func (v *Viterbi32) EvalPath() (ViterbiPath, error) {
+	return v.evalPath(false)
+}
+
+// EvalPathLogProbabilities is the same as EvalPath, but when every probability is logarithmic
+func (v *Viterbi32) EvalPathLogProbabilities() (ViterbiPath, error) {
+	return v.evalPath(true)
+}
+
+func (v *Viterbi32) evalPath(logSpace bool) (ViterbiPath, error) {
+	if len(v.states) == 0 {
+		return ViterbiPath{}, ErrNoStates
+	}
+	if len(v.observations) == 0 {
+		return ViterbiPath{}, ErrNoObservations
+	}
+
+	// Trellis is kept as back-pointers of reachable cells only (ordered by index of state), probabilities and reachability of two columns are enough
+	n := len(v.states)
+	prevProbs, probs := make([]float32, n), make([]float32, n)
+	prevReached, reached := make([]bool, n), make([]bool, n)
+	back := make([][]backPointer32, len(v.observations))
+	logScale := 0.0
+	for t := range v.observations {
+		for i, s := range v.states {
+			reached[i] = false
+			emissionProb, ok, err := v.emissionProbability(s, t, logSpace)
+			if err != nil {
+				return ViterbiPath{}, err
+			}
+			if !ok {
+				// No emission for current state of current observation
+				continue
+			}
+			best, bestProb := int32(-1), float32(0)
+			if t == 0 {
+				startProb, ok := v.startProbabilities[s.ID()]
+				if !ok {
+					continue
+				}
+				if !validProbability(logSpace, float64(startProb)) {
+					return ViterbiPath{}, fmt.Errorf("%w: start probability %v of state %v", ErrInvalidProbability, startProb, s)
+				}
+				// The first column has no predecessors: any non-negative index marks cell as reachable
+				best, bestProb = 0, startProb
+			}
+			for j, r := range v.states {
+				if t == 0 || !prevReached[j] {
+					// No previous observation or no probability from state to observation
+					continue
+				}
+				transitionProb, ok := v.transitionProbabilities[TransitionHash{r.ID(), s.ID()}]
+				if !ok {
+					// No transition between states
+					continue
+				}
+				if !validProbability(logSpace, float64(transitionProb)) {
+					return ViterbiPath{}, fmt.Errorf("%w: transition probability %v from state %v to state %v", ErrInvalidProbability, transitionProb, r, s)
+				}
+				prob := combine32(logSpace, prevProbs[j], transitionProb)
+				if best < 0 || preferLowestID(float64(prob), r, float64(bestProb), v.states[best]) {
+					best, bestProb = int32(j), prob
+				}
+			}
+			if best < 0 {
+				// State is unreachable from any state of previous observation
+				continue
+			}
+			prob := combine32(logSpace, bestProb, emissionProb)
+			if impossible(logSpace, float64(prob)) {
+				continue
+			}
+			probs[i] = prob
+			reached[i] = true
+			back[t] = append(back[t], backPointer32{state: int32(i), prev: best})
+		}
+		if len(back[t]) == 0 {
+			return ViterbiPath{}, v.pathBroken(t)
+		}
+		if !logSpace {
+			logScale += v.rescaleColumn(probs, reached)
+		}
+		prevProbs, probs = probs, prevProbs
+		prevReached, reached = reached, prevReached
+	}
+
+	last := len(back) - 1
+	best := -1
+	for i, st := range v.states {
+		if !prevReached[i] {
+			continue
+		}
+		if best < 0 || preferLowestID(float64(prevProbs[i]), st, float64(prevProbs[best]), v.states[best]) {
+			best = i
+		}
+	}
+	maxPr := float64(prevProbs[best])
+	path := make([]State, len(v.observations))
+	for t := last; t >= 0; t-- {
+		path[t] = v.states[best]
+		column := back[t]
+		k := sort.Search(len(column), func(k int) bool { return column[k].state >= int32(best) })
+		best = int(column[k].prev)
+	}
+	if logSpace {
+		return ViterbiPath{Probability: maxPr, LogProbability: maxPr, Path: path}, nil
+	}
+	return ViterbiPath{Probability: maxPr * math.Exp(logScale), LogProbability: math.Log(maxPr) + logScale, Path: path}, nil
+}
+
+// backPointer32 is reachable cell of Viterbi32 trellis: index of the state and index of its best predecessor
+type backPointer32 struct {
+	state int32
+	prev  int32
+}
+
+// rescaleColumn divides classic probabilities of reachable states by the maximum one. It returns logarithm of the divisor
+func (v *Viterbi32) rescaleColumn(probs []float32, reached []bool) float64 {
+	maxProb := float32(0)
+	for i := range probs {
+		if reached[i] && probs[i] > maxProb {
+			maxProb = probs[i]
+		}
+	}
+	for i := range probs {
+		if reached[i] {
+			probs[i] /= maxProb
+		}
+	}
+	return math.Log(float64(maxProb))
+}
+
+// emissionProbability returns probability of the state to emit observation with index t
+func (v *Viterbi32) emissionProbability(s State, t int, logSpace bool) (float32, bool, error) {
+	emissionProb, ok := v.emissionProbabilities[EmissionHash{s.ID(), v.observations[t].ID()}]
+	if !ok {
+		return 0, false, nil
+	}
+	if !validProbability(logSpace, float64(emissionProb)) {
+		return 0, false, fmt.Errorf("%w: emission probability %v of state %v for observation %v", ErrInvalidProbability, emissionProb, s, v.observations[t])
+	}
+	return emissionProb, true, nil
+}
+
+// pathBroken returns *PathBrokenError for observation t
+func (v *Viterbi32) pathBroken(t int) error {
+	unreachable := []State{}
+	for _, s := range v.states {
+		if _, ok := v.emissionProbabilities[EmissionHash{s.ID(), v.observations[t].ID()}]; ok {
+			unreachable = append(unreachable, s)
+		}
+	}
+	return &PathBrokenError{
+		ObservationIndex:  t,
+		Observation:       v.observations[t],
+		UnreachableStates: unreachable,
+	}
+}
+
+// combine32 is the same as combine, but for float32
+func combine32(logSpace bool, a, b float32) float32 {
+	if logSpace {
+		return a + b
+	}
+	return a * b
+}
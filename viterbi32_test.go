@@ -0,0 +1,88 @@
+package viterbi
+
+import (
+	"math"
+	"testing"
+)
+
+// model32 copies model into Viterbi32
+func model32(v *Viterbi) *Viterbi32 {
+	v32 := New32()
+	for _, st := range v.states {
+		v32.AddState(st)
+	}
+	for _, obs := range v.observations {
+		v32.AddObservation(obs)
+	}
+	for id, val := range v.startProbabilities {
+		v32.startProbabilities[id] = float32(val)
+	}
+	for key, val := range v.emissionProbabilities {
+		v32.emissionProbabilities[key] = float32(val)
+	}
+	for key, val := range v.transitionProbabilities {
+		v32.transitionProbabilities[key] = float32(val)
+	}
+	return v32
+}
+
+func TestViterbi32EvalPath(t *testing.T) {
+	v, _, incomingObservations := healthModel()
+	// Long sequence underflows float32 without rescaling
+	for i := 0; i < 300; i++ {
+		for j := range incomingObservations {
+			v.AddObservation(incomingObservations[j])
+		}
+	}
+	// Only third of states could emit every observation, so most of trellis cells are unreachable
+	sparse := randomModel(20, 15, 11)
+	for key := range sparse.emissionProbabilities {
+		if (key.State+key.observation)%3 != 0 {
+			delete(sparse.emissionProbabilities, key)
+		}
+	}
+	for _, v := range []*Viterbi{v, randomModel(20, 15, 7), sparse} {
+		expected, err := v.EvalPath()
+		if err != nil {
+			t.Fatal(err)
+		}
+		vpath, err := model32(v).EvalPath()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if math.Abs(vpath.LogProbability-expected.LogProbability) > 1e-5*math.Abs(expected.LogProbability) {
+			t.Error(
+				"Logarithmic probability has to be", expected.LogProbability, ", but got", vpath.LogProbability,
+			)
+		}
+		if len(vpath.Path) != len(expected.Path) {
+			t.Fatal(
+				"Expected", len(expected.Path), "states, but got:", len(vpath.Path),
+			)
+		}
+		for i := range expected.Path {
+			if vpath.Path[i] != expected.Path[i] {
+				t.Error(
+					"State", i, "has to be", expected.Path[i], "but got", vpath.Path[i],
+				)
+				break
+			}
+		}
+
+		logPath, err := model32(logModel(v)).EvalPathLogProbabilities()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if math.Abs(logPath.Probability-expected.LogProbability) > 1e-5*math.Abs(expected.LogProbability) {
+			t.Error(
+				"Logarithmic probability has to be", expected.LogProbability, ", but got", logPath.Probability,
+			)
+		}
+	}
+
+	if _, err := New32().EvalPath(); err != ErrNoStates {
+		t.Error(
+			"Error has to be ErrNoStates, but got", err,
+		)
+	}
+}
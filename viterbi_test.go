@@ -1,7 +1,11 @@
 package viterbi
 
 import (
+	"context"
+	"errors"
 	"fmt"
+	"math"
+	"strings"
 	"testing"
 )
 
@@ -58,7 +62,10 @@ func TestViterbiEvalPath(t *testing.T) {
 	v.PutTransitionProbability(incStates[1], incStates[0], 0.4)
 	v.PutTransitionProbability(incStates[1], incStates[1], 0.6)
 
-	vpath := v.EvalPath()
+	vpath, err := v.EvalPath()
+	if err != nil {
+		t.Fatal(err)
+	}
 
 	if len(vpath.Path) != 3 {
 		t.Error(
@@ -176,7 +183,10 @@ func TestFindPath(t *testing.T) {
 	v.PutTransitionProbability(incStates["13"], incStates["18"], 0.000177)
 	v.PutTransitionProbability(incStates["13"], incStates["19"], 0.000101)
 
-	vpath := v.EvalPath()
+	vpath, err := v.EvalPath()
+	if err != nil {
+		t.Fatal(err)
+	}
 
 	fmt.Println("prob:", vpath.Probability)
 	fmt.Println("path:")
@@ -306,7 +316,10 @@ func TestViterbiEvalPathLogProbabilities(t *testing.T) {
 	v.PutTransitionProbability(incStates[6], incStates[8], -626.5028606174612)
 	// fmt.Printf("Transition from rp33 (6) to rp42 (8) is %f\n", -626.5028606174612)
 
-	vpath := v.EvalPathLogProbabilities()
+	vpath, err := v.EvalPathLogProbabilities()
+	if err != nil {
+		t.Fatal(err)
+	}
 	fmt.Println("prob:", vpath.Probability)
 	fmt.Println("path:")
 	for i := range vpath.Path {
@@ -344,3 +357,893 @@ func TestViterbiEvalPathLogProbabilities(t *testing.T) {
 		)
 	}
 }
+
+func TestViterbiEvalPathTieBreaking(t *testing.T) {
+	var (
+		incStates = []CustomState{
+			CustomState{Name: "second", id: 2},
+			CustomState{Name: "first", id: 1},
+		}
+		observations = []CustomObservation{
+			CustomObservation{Name: "o1", id: 1},
+			CustomObservation{Name: "o2", id: 2},
+		}
+	)
+	v := New()
+	for i := range incStates {
+		v.AddState(incStates[i])
+	}
+	for i := range observations {
+		v.AddObservation(observations[i])
+	}
+	for i := range incStates {
+		v.PutStartProbability(incStates[i], 0.5)
+		for j := range observations {
+			v.PutEmissionProbability(incStates[i], observations[j], 0.5)
+		}
+		for j := range incStates {
+			v.PutTransitionProbability(incStates[i], incStates[j], 0.5)
+		}
+	}
+
+	for i := 0; i < 20; i++ {
+		vpath, err := v.EvalPath()
+		if err != nil {
+			t.Fatal(err)
+		}
+		vpathLog, err := v.EvalPathLogProbabilities()
+		if err != nil {
+			t.Fatal(err)
+		}
+		for j := range vpath.Path {
+			if vpath.Path[j] != incStates[1] {
+				t.Error(
+					"State", j, "has to be 'first' (lowest ID), but got", vpath.Path[j],
+				)
+			}
+		}
+		for j := range vpathLog.Path {
+			if vpathLog.Path[j] != incStates[1] {
+				t.Error(
+					"State", j, "has to be 'first' (lowest ID) in logarithmic evaluation, but got", vpathLog.Path[j],
+				)
+			}
+		}
+	}
+}
+
+// healthModel returns classic example of HMM from https://en.wikipedia.org/wiki/Viterbi_algorithm#Example
+func healthModel() (*Viterbi, []CustomState, []CustomObservation) {
+	var (
+		incStates = []CustomState{
+			CustomState{Name: "Healty", id: 1},
+			CustomState{Name: "Fever", id: 2},
+		}
+		incomingObservations = []CustomObservation{
+			CustomObservation{Name: "normal", id: 1},
+			CustomObservation{Name: "cold", id: 2},
+			CustomObservation{Name: "dizzy", id: 3},
+		}
+	)
+	v := New()
+	for i := range incStates {
+		v.AddState(incStates[i])
+	}
+	for i := range incomingObservations {
+		v.AddObservation(incomingObservations[i])
+	}
+
+	v.PutStartProbability(incStates[0], 0.6)
+	v.PutStartProbability(incStates[1], 0.4)
+
+	v.PutEmissionProbability(incStates[0], incomingObservations[0], 0.5)
+	v.PutEmissionProbability(incStates[0], incomingObservations[1], 0.4)
+	v.PutEmissionProbability(incStates[0], incomingObservations[2], 0.1)
+	v.PutEmissionProbability(incStates[1], incomingObservations[0], 0.1)
+	v.PutEmissionProbability(incStates[1], incomingObservations[1], 0.3)
+	v.PutEmissionProbability(incStates[1], incomingObservations[2], 0.6)
+
+	v.PutTransitionProbability(incStates[0], incStates[0], 0.7)
+	v.PutTransitionProbability(incStates[0], incStates[1], 0.3)
+	v.PutTransitionProbability(incStates[1], incStates[0], 0.4)
+	v.PutTransitionProbability(incStates[1], incStates[1], 0.6)
+	return v, incStates, incomingObservations
+}
+
+func TestViterbiEvalPathErrors(t *testing.T) {
+	v := New()
+	if _, err := v.EvalPath(); err != ErrNoStates {
+		t.Error(
+			"Error has to be ErrNoStates, but got", err,
+		)
+	}
+	v.AddState(CustomState{Name: "s", id: 1})
+	if _, err := v.EvalPath(); err != ErrNoObservations {
+		t.Error(
+			"Error has to be ErrNoObservations, but got", err,
+		)
+	}
+	v.AddObservation(CustomObservation{Name: "o", id: 1})
+	if _, err := v.EvalPath(); !errors.Is(err, ErrPathBroken) {
+		t.Error(
+			"Error has to be ErrPathBroken, but got", err,
+		)
+	}
+	v.PutStartProbability(CustomState{Name: "s", id: 1}, 1.5)
+	v.PutEmissionProbability(CustomState{Name: "s", id: 1}, CustomObservation{Name: "o", id: 1}, 1.0)
+	if _, err := v.EvalPath(); !errors.Is(err, ErrInvalidProbability) {
+		t.Error(
+			"Error has to be ErrInvalidProbability, but got", err,
+		)
+	}
+}
+
+func TestViterbiNaNProbability(t *testing.T) {
+	v, incStates, incomingObservations := healthModel()
+	v.emissionProbabilities[EmissionHash{incStates[1].ID(), incomingObservations[1].ID()}] = math.NaN()
+	_, err := v.EvalPath()
+	if !errors.Is(err, ErrInvalidProbability) {
+		t.Fatal(
+			"Error has to be ErrInvalidProbability, but got", err,
+		)
+	}
+	if msg := err.Error(); !strings.Contains(msg, fmt.Sprint(incStates[1])) || !strings.Contains(msg, fmt.Sprint(incomingObservations[1])) {
+		t.Error(
+			"Error has to name state and observation, but got", msg,
+		)
+	}
+
+	logV := logModel(v)
+	if _, err := logV.EvalPathLogProbabilities(); !errors.Is(err, ErrInvalidProbability) {
+		t.Error(
+			"Error has to be ErrInvalidProbability in logarithmic evaluation, but got", err,
+		)
+	}
+
+	// Impossible emission weighted by zero is certain in both evaluations instead of NaN
+	v, incStates, incomingObservations = healthModel()
+	v.emissionProbabilities[EmissionHash{incStates[0].ID(), incomingObservations[2].ID()}] = 0
+	v.SetEmissionWeight(0)
+	vpath, err := v.EvalPath()
+	if err != nil {
+		t.Fatal(err)
+	}
+	logPath, err := v.ToLog().EvalPathLogProbabilities()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if math.Abs(logPath.Probability-vpath.LogProbability) > 1e-12 {
+		t.Error(
+			"Logarithmic probability has to be", vpath.LogProbability, ", but got", logPath.Probability,
+		)
+	}
+}
+
+func TestViterbiRemoveState(t *testing.T) {
+	v, incStates, incomingObservations := healthModel()
+	expected, err := v.EvalPath()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	extraState := CustomState{Name: "Extra", id: 3}
+	extraObservation := CustomObservation{Name: "extra", id: 4}
+	v.AddState(extraState)
+	v.PutStartProbability(extraState, 1.0)
+	v.PutEmissionProbability(extraState, incomingObservations[0], 1.0)
+	v.PutEmissionProbability(extraState, incomingObservations[1], 1.0)
+	v.PutEmissionProbability(extraState, incomingObservations[2], 1.0)
+	v.PutTransitionProbability(extraState, extraState, 1.0)
+	v.PutTransitionProbability(incStates[0], extraState, 1.0)
+	v.AddObservation(extraObservation)
+	v.PutEmissionProbability(incStates[0], extraObservation, 1.0)
+
+	v.RemoveState(extraState)
+	v.RemoveObservation(extraObservation)
+	// Removing missing entries is no-op
+	v.RemoveState(extraState)
+	v.RemoveObservation(extraObservation)
+
+	if len(v.states) != len(incStates) || len(v.observations) != len(incomingObservations) {
+		t.Error(
+			"Expected", len(incStates), "states and", len(incomingObservations), "observations, but got:", len(v.states), len(v.observations),
+		)
+	}
+	if len(v.startProbabilities) != 2 || len(v.emissionProbabilities) != 6 || len(v.transitionProbabilities) != 4 {
+		t.Error(
+			"Probabilities referencing removed entries have to be purged",
+		)
+	}
+	vpath, err := v.EvalPath()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if vpath.Probability != expected.Probability {
+		t.Error(
+			"Probability has to be", expected.Probability, "but got", vpath.Probability,
+		)
+	}
+	for i := range expected.Path {
+		if vpath.Path[i] != expected.Path[i] {
+			t.Error(
+				"State", i, "has to be", expected.Path[i], "but got", vpath.Path[i],
+			)
+		}
+	}
+}
+
+func TestViterbiReset(t *testing.T) {
+	v, incStates, incomingObservations := healthModel()
+
+	v.ResetObservations()
+	if len(v.observations) != 0 || len(v.emissionProbabilities) != 0 {
+		t.Error(
+			"Observations and emissions have to be cleared",
+		)
+	}
+	if len(v.states) != 2 || len(v.startProbabilities) != 2 || len(v.transitionProbabilities) != 4 {
+		t.Error(
+			"States, start and transition probabilities have to be kept",
+		)
+	}
+	if _, err := v.EvalPath(); err != ErrNoObservations {
+		t.Error(
+			"Error has to be ErrNoObservations, but got", err,
+		)
+	}
+
+	for i := range incomingObservations {
+		v.AddObservation(incomingObservations[i])
+	}
+	v.PutEmissionProbability(incStates[0], incomingObservations[0], 0.5)
+	v.PutEmissionProbability(incStates[0], incomingObservations[1], 0.4)
+	v.PutEmissionProbability(incStates[0], incomingObservations[2], 0.1)
+	v.PutEmissionProbability(incStates[1], incomingObservations[0], 0.1)
+	v.PutEmissionProbability(incStates[1], incomingObservations[1], 0.3)
+	v.PutEmissionProbability(incStates[1], incomingObservations[2], 0.6)
+	vpath, err := v.EvalPath()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if vpath.Probability != 0.01512 {
+		t.Error(
+			"Probability has to be 0.01512, but got", vpath.Probability,
+		)
+	}
+
+	v.IncStart(incStates[0])
+	v.IncEmission(incStates[0], incomingObservations[0])
+	v.IncTransition(incStates[0], incStates[1])
+	v.SetDefaultEmissionProbability(0.1)
+	v.SetDefaultTransitionProbability(0.1)
+	v.SetUnknownEmission(0.1)
+	v.SetEmissionWeight(2)
+	v.SetTransitionFunc(func(from, to State) (float64, bool) { return 0.5, true })
+	v.Reset()
+	if len(v.states) != 0 || len(v.observations) != 0 || len(v.startProbabilities) != 0 || len(v.emissionProbabilities) != 0 || len(v.transitionProbabilities) != 0 {
+		t.Error(
+			"Model has to be empty after reset",
+		)
+	}
+	if len(v.startCounts) != 0 || len(v.emissionCounts) != 0 || len(v.transitionCounts) != 0 {
+		t.Error(
+			"Counts have to be cleared after reset",
+		)
+	}
+	if v.defaultEmission != nil || v.defaultTransition != nil || v.unknownEmission != nil || v.emissionWeight != nil || v.transitionFunc != nil {
+		t.Error(
+			"Default and computed probabilities have to be cleared after reset",
+		)
+	}
+	if _, err := v.EvalPath(); err != ErrNoStates {
+		t.Error(
+			"Error has to be ErrNoStates, but got", err,
+		)
+	}
+
+	// Counts accumulated before reset have not to leak into the next model
+	v.AddState(incStates[1])
+	v.IncStart(incStates[1])
+	v.IncTransition(incStates[1], incStates[1])
+	if err := v.Finalize(0); err != nil {
+		t.Fatal(err)
+	}
+	if _, ok := v.GetStartProbability(incStates[0]); ok || len(v.transitionProbabilities) != 1 {
+		t.Error(
+			"Only counts accumulated after reset have to be finalized, but got", v.startProbabilities, v.transitionProbabilities,
+		)
+	}
+}
+
+func TestViterbiGetProbabilities(t *testing.T) {
+	v, incStates, incomingObservations := healthModel()
+	if val, ok := v.GetStartProbability(incStates[0]); !ok || val != 0.6 {
+		t.Error(
+			"Start probability of 'Healty' has to be 0.6, but got", val, ok,
+		)
+	}
+	if val, ok := v.GetEmissionProbability(incStates[1], incomingObservations[2]); !ok || val != 0.6 {
+		t.Error(
+			"Emission probability of 'Fever' for 'dizzy' has to be 0.6, but got", val, ok,
+		)
+	}
+	if val, ok := v.GetTransitionProbability(incStates[1], incStates[0]); !ok || val != 0.4 {
+		t.Error(
+			"Transition probability from 'Fever' to 'Healty' has to be 0.4, but got", val, ok,
+		)
+	}
+	unknown := CustomState{Name: "Unknown", id: 3}
+	if _, ok := v.GetStartProbability(unknown); ok {
+		t.Error(
+			"Start probability of unknown state has not to be set",
+		)
+	}
+	if _, ok := v.GetEmissionProbability(unknown, incomingObservations[0]); ok {
+		t.Error(
+			"Emission probability of unknown state has not to be set",
+		)
+	}
+	if _, ok := v.GetTransitionProbability(incStates[0], unknown); ok {
+		t.Error(
+			"Transition probability to unknown state has not to be set",
+		)
+	}
+}
+
+func TestViterbiEvalPathContext(t *testing.T) {
+	v, _, _ := healthModel()
+	expected, err := v.EvalPath()
+	if err != nil {
+		t.Fatal(err)
+	}
+	vpath, err := v.EvalPathContext(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if vpath.Probability != expected.Probability {
+		t.Error(
+			"Probability has to be", expected.Probability, "but got", vpath.Probability,
+		)
+	}
+	for i := range expected.Path {
+		if vpath.Path[i] != expected.Path[i] {
+			t.Error(
+				"State", i, "has to be", expected.Path[i], "but got", vpath.Path[i],
+			)
+		}
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if _, err := v.EvalPathContext(ctx); err != context.Canceled {
+		t.Error(
+			"Error has to be context.Canceled, but got", err,
+		)
+	}
+	if _, err := logModel(v).EvalPathLogProbabilitiesContext(ctx); err != context.Canceled {
+		t.Error(
+			"Error has to be context.Canceled, but got", err,
+		)
+	}
+}
+
+func TestViterbiAddStateChecked(t *testing.T) {
+	v := New()
+	if err := v.AddStateChecked(CustomState{Name: "a", id: 1}); err != nil {
+		t.Fatal(err)
+	}
+	if err := v.AddStateChecked(CustomState{Name: "b", id: 2}); err != nil {
+		t.Fatal(err)
+	}
+	if err := v.AddStateChecked(CustomState{Name: "another a", id: 1}); !errors.Is(err, ErrDuplicateState) {
+		t.Error(
+			"Error has to be ErrDuplicateState, but got", err,
+		)
+	}
+	if len(v.states) != 2 {
+		t.Error(
+			"Expected 2 states, but got:", len(v.states),
+		)
+	}
+}
+
+func TestViterbiAddObservationChecked(t *testing.T) {
+	v := New()
+	if err := v.AddObservationChecked(CustomObservation{Name: "a", id: 1}); err != nil {
+		t.Fatal(err)
+	}
+	if err := v.AddObservationChecked(CustomObservation{Name: "b", id: 2}); err != nil {
+		t.Fatal(err)
+	}
+	if err := v.AddObservationChecked(CustomObservation{Name: "another a", id: 1}); !errors.Is(err, ErrDuplicateObservation) {
+		t.Error(
+			"Error has to be ErrDuplicateObservation, but got", err,
+		)
+	}
+	if err := v.AddObservationChecked(nil); err != ErrNilObservation {
+		t.Error(
+			"Error has to be ErrNilObservation, but got", err,
+		)
+	}
+	if len(v.observations) != 2 {
+		t.Error(
+			"Expected 2 observations, but got:", len(v.observations),
+		)
+	}
+	if obs, ok := v.ObservationByID(2); !ok || obs.(CustomObservation).Name != "b" {
+		t.Error(
+			"Observation with ID 2 has to be 'b', but got", obs, ok,
+		)
+	}
+	if obs, ok := v.ObservationByID(3); ok {
+		t.Error(
+			"Observation with ID 3 has not been added, but got", obs,
+		)
+	}
+}
+
+func TestViterbiPutEmissionProbabilityChecked(t *testing.T) {
+	v := New()
+	healthy := CustomState{Name: "Healthy", id: 1}
+	normal := CustomObservation{Name: "normal", id: 10}
+	v.AddState(healthy)
+	v.AddObservation(normal)
+	if err := v.PutEmissionProbabilityChecked(healthy, normal, 0.5); err != nil {
+		t.Fatal(err)
+	}
+	// Arguments are swapped
+	swapped := v.PutEmissionProbabilityChecked(CustomState{Name: "normal", id: 10}, CustomObservation{Name: "Healthy", id: 1}, 0.5)
+	if !errors.Is(swapped, ErrUnknownState) {
+		t.Error(
+			"Error has to be ErrUnknownState, but got", swapped,
+		)
+	}
+	if err := v.PutEmissionProbabilityChecked(healthy, CustomObservation{Name: "cold", id: 11}, 0.5); !errors.Is(err, ErrUnknownObservation) {
+		t.Error(
+			"Error has to be ErrUnknownObservation, but got", err,
+		)
+	}
+	if err := v.PutEmissionProbabilityChecked(nil, normal, 0.5); err != ErrNilState {
+		t.Error(
+			"Error has to be ErrNilState, but got", err,
+		)
+	}
+	if len(v.emissionProbabilities) != 1 {
+		t.Error(
+			"Expected single emission probability, but got", v.emissionProbabilities,
+		)
+	}
+}
+
+func TestViterbiIdentityByID(t *testing.T) {
+	v, incStates, incomingObservations := healthModel()
+	// Fresh values with the same IDs (but different names) refer to the same states and observations
+	healthy := CustomState{Name: "Healthy (fresh)", id: incStates[0].ID()}
+	dizzy := CustomObservation{Name: "dizzy (fresh)", id: incomingObservations[2].ID()}
+	if val, ok := v.GetEmissionProbability(healthy, dizzy); !ok || val != 0.1 {
+		t.Error(
+			"Emission probability has to be found by IDs, but got", val, ok,
+		)
+	}
+
+	fresh := New()
+	for i := range incStates {
+		fresh.AddState(incStates[i])
+	}
+	for i := range incomingObservations {
+		fresh.AddObservation(incomingObservations[i])
+	}
+	start, emission, transition, stateOrder, obsOrder := v.ToMatrices()
+	for i := range stateOrder {
+		st := CustomState{Name: "fresh", id: stateOrder[i].ID()}
+		fresh.PutStartProbability(st, start[i])
+		for j := range obsOrder {
+			fresh.PutEmissionProbability(st, CustomObservation{Name: "fresh", id: obsOrder[j].ID()}, emission[i][j])
+		}
+		for j := range stateOrder {
+			fresh.PutTransitionProbability(st, CustomState{Name: "fresh", id: stateOrder[j].ID()}, transition[i][j])
+		}
+	}
+	vpath, err := fresh.EvalPath()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if vpath.Probability != 0.01512 {
+		t.Error(
+			"Probability has to be 0.01512, but got", vpath.Probability,
+		)
+	}
+
+	// Predecessors are matched by IDs too, whether states have been added before or after them
+	fever := CustomState{Name: "Fever (fresh)", id: incStates[1].ID()}
+	fresh.SetPredecessors(incStates[1], []State{healthy, fever})
+	late := New()
+	late.SetPredecessors(incStates[0], []State{healthy, fever})
+	late.SetPredecessors(incStates[1], []State{healthy, fever})
+	for i := range incStates {
+		late.AddState(incStates[i])
+	}
+	for i := range incomingObservations {
+		late.AddObservation(incomingObservations[i])
+	}
+	late.startProbabilities, late.emissionProbabilities, late.transitionProbabilities = fresh.startProbabilities, fresh.emissionProbabilities, fresh.transitionProbabilities
+	for _, m := range []*Viterbi{fresh, late} {
+		vpath, err := m.EvalPath()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if vpath.Probability != 0.01512 {
+			t.Error(
+				"Probability with fresh predecessors has to be 0.01512, but got", vpath.Probability,
+			)
+		}
+	}
+}
+
+func TestViterbiZeroProbabilitiesSkipped(t *testing.T) {
+	v, incStates, incomingObservations := healthModel()
+	dead := CustomState{Name: "Dead", id: 3}
+	v.AddState(dead)
+	v.PutStartProbability(dead, 0)
+	for i := range incomingObservations {
+		v.PutEmissionProbability(dead, incomingObservations[i], 1.0)
+	}
+	v.PutTransitionProbability(dead, dead, 1.0)
+
+	_, trellis, err := v.EvalPathWithTrellis()
+	if err != nil {
+		t.Fatal(err)
+	}
+	for t0 := range trellis {
+		for _, cell := range trellis[t0] {
+			if cell.State.ID() == dead.ID() {
+				t.Error(
+					"State with zero start probability has not to be in trellis column", t0,
+				)
+			}
+		}
+	}
+
+	// The same as -Inf in logarithmic mode: zero start probability makes state unable to start the path
+	single := New()
+	single.AddState(incStates[0])
+	single.AddObservation(incomingObservations[0])
+	single.PutStartProbability(incStates[0], 0)
+	single.PutEmissionProbability(incStates[0], incomingObservations[0], 1.0)
+	if _, err := single.EvalPath(); !errors.Is(err, ErrPathBroken) {
+		t.Error(
+			"Error has to be ErrPathBroken, but got", err,
+		)
+	}
+	if _, err := single.ToLog().EvalPathLogProbabilities(); !errors.Is(err, ErrPathBroken) {
+		t.Error(
+			"Error has to be ErrPathBroken, but got", err,
+		)
+	}
+}
+
+func TestViterbiNoValidPath(t *testing.T) {
+	v, incStates, _ := healthModel()
+	// Every path passes through zero-probability transition
+	for _, from := range incStates {
+		for _, to := range incStates {
+			delete(v.transitionProbabilities, TransitionHash{from.ID(), to.ID()})
+			v.PutTransitionProbability(from, to, 0)
+		}
+	}
+	if _, err := v.EvalPath(); !errors.Is(err, ErrNoValidPath) {
+		t.Error(
+			"Error has to be ErrNoValidPath, but got", err,
+		)
+	}
+
+	// Trellis with zero probabilities only
+	V := []map[State]ViterbiVal{
+		{incStates[0]: {prob: 0}, incStates[1]: {prob: 0}},
+	}
+	if _, err := v.backtrack(V, false, 0); err != ErrNoValidPath {
+		t.Error(
+			"Error has to be ErrNoValidPath, but got", err,
+		)
+	}
+	V[0] = map[State]ViterbiVal{incStates[0]: {prob: math.Inf(-1)}}
+	if _, err := v.backtrack(V, true, 0); err != ErrNoValidPath {
+		t.Error(
+			"Error has to be ErrNoValidPath, but got", err,
+		)
+	}
+}
+
+func TestViterbiPutEmissionProbabilityAt(t *testing.T) {
+	v, incStates, incomingObservations := healthModel()
+	// Only the first observation is made more probable for 'Fever'
+	v.PutEmissionProbabilityAt(incStates[1], 0, 1.0)
+	v.PutEmissionProbabilityAt(incStates[0], 0, 0.01)
+	vpath, err := v.EvalPath()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if vpath.Path[0].ID() != incStates[1].ID() {
+		t.Error(
+			"State on position 0 has to be", incStates[1], ", but got", vpath.Path[0],
+		)
+	}
+	if val, _ := v.GetEmissionProbability(incStates[1], incomingObservations[0]); val != 0.1 {
+		t.Error(
+			"Emission probability which is not time-indexed has to stay 0.1, but got", val,
+		)
+	}
+
+	v.ResetObservations()
+	for i := range incomingObservations {
+		v.AddObservation(incomingObservations[i])
+	}
+	if len(v.timedEmissions) != 0 {
+		t.Error(
+			"Time-indexed emissions have to be cleared, but got", v.timedEmissions,
+		)
+	}
+}
+
+func TestViterbiPutNoEmissionAt(t *testing.T) {
+	v, incStates, _ := healthModel()
+	// 'dizzy' is missing: the last state follows transition from 'Healty' only
+	v.PutNoEmissionAt(2)
+	vpath, err := v.EvalPath()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if vpath.Path[2].ID() != incStates[0].ID() {
+		t.Error(
+			"State on position 2 has to be", incStates[0], ", but got", vpath.Path[2],
+		)
+	}
+	if math.Abs(vpath.Probability-0.0588) > 1e-12 {
+		t.Error(
+			"Probability has to be 0.0588, but got", vpath.Probability,
+		)
+	}
+	logPath, err := v.ToLog().EvalPathLogProbabilities()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if math.Abs(logPath.Probability-math.Log(0.0588)) > 1e-12 {
+		t.Error(
+			"Logarithmic probability has to be", math.Log(0.0588), ", but got", logPath.Probability,
+		)
+	}
+
+	v.ResetObservations()
+	if v.noEmissions != nil {
+		t.Error(
+			"Missing observations have to be cleared, but got", v.noEmissions,
+		)
+	}
+}
+
+func TestViterbiValGetters(t *testing.T) {
+	_, incStates, _ := healthModel()
+	val := ViterbiVal{prob: 0.3, prev: incStates[0]}
+	if val.Prob() != 0.3 {
+		t.Error(
+			"Probability has to be 0.3, but got", val.Prob(),
+		)
+	}
+	if val.Prev() != incStates[0] {
+		t.Error(
+			"Previous state has to be", incStates[0], ", but got", val.Prev(),
+		)
+	}
+	if (ViterbiVal{}).Prev() != nil {
+		t.Error(
+			"Previous state of the first observation has to be nil",
+		)
+	}
+}
+
+func TestViterbiPutEndProbability(t *testing.T) {
+	v, incStates, _ := healthModel()
+	// Without end probabilities path ends in 'Fever' with 0.01512 versus 0.00588 for 'Healty'
+	v.PutEndProbability(incStates[1], 0.1)
+
+	vpath, err := v.EvalPath()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if math.Abs(vpath.Probability-0.00588) > 1e-12 {
+		t.Error(
+			"Probability has to be 0.00588, but got", vpath.Probability,
+		)
+	}
+	for i := range vpath.Path {
+		if vpath.Path[i].ID() != incStates[0].ID() {
+			t.Error(
+				"State on position", i, "has to be", incStates[0], ", but got", vpath.Path[i],
+			)
+		}
+	}
+
+	paths, err := v.EvalPathN(1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	compactPath, err := v.EvalPathCompact()
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, path := range []ViterbiPath{paths[0], compactPath} {
+		if math.Abs(path.Probability-vpath.Probability) > 1e-12 {
+			t.Error(
+				"Probability has to be", vpath.Probability, ", but got", path.Probability,
+			)
+		}
+	}
+
+	data, err := v.ToJSON()
+	if err != nil {
+		t.Fatal(err)
+	}
+	restored, err := FromJSON(data, func(id int) State { return incStates[id-1] }, func(id int) Observation { return CustomObservation{id: id} })
+	if err != nil {
+		t.Fatal(err)
+	}
+	if val, ok := restored.GetEndProbability(incStates[1]); !ok || val != 0.1 {
+		t.Error(
+			"End probability has to be restored as 0.1, but got", val, ok,
+		)
+	}
+
+	v.PutEndProbability(incStates[0], 0)
+	v.PutEndProbability(incStates[1], 0)
+	if _, err := v.EvalPath(); !errors.Is(err, ErrNoValidPath) {
+		t.Error(
+			"Error has to be ErrNoValidPath, but got", err,
+		)
+	}
+}
+
+func TestViterbiAddProbabilities(t *testing.T) {
+	var (
+		healthy = CustomState{Name: "Healty", id: 1}
+		fever   = CustomState{Name: "Fever", id: 2}
+		normal  = CustomObservation{Name: "normal", id: 1}
+		dizzy   = CustomObservation{Name: "dizzy", id: 3}
+	)
+	v := New()
+	v.AddState(healthy)
+	v.AddState(fever)
+	// Counts of observed sequences
+	for _, seq := range [][]State{{healthy, healthy, fever}, {healthy, fever, fever}, {fever, fever, healthy}} {
+		v.AddStartProbability(seq[0], 1)
+		for i := 1; i < len(seq); i++ {
+			v.AddTransitionProbability(seq[i-1], seq[i], 1)
+		}
+	}
+	v.AddEmissionProbability(healthy, normal, 2)
+	v.AddEmissionProbability(healthy, normal, 2)
+	v.AddEmissionProbability(healthy, dizzy, 1)
+	v.AddEmissionProbability(fever, dizzy, 3)
+
+	if val, _ := v.GetStartProbability(healthy); val != 2 {
+		t.Error(
+			"Start count of 'Healty' has to be 2, but got", val,
+		)
+	}
+	if val, _ := v.GetTransitionProbability(fever, fever); val != 2 {
+		t.Error(
+			"Transition count from 'Fever' to 'Fever' has to be 2, but got", val,
+		)
+	}
+	if val, _ := v.GetEmissionProbability(healthy, normal); val != 4 {
+		t.Error(
+			"Emission count of 'normal' by 'Healty' has to be 4, but got", val,
+		)
+	}
+
+	if err := v.Normalize(); err != nil {
+		t.Fatal(err)
+	}
+	if val, _ := v.GetEmissionProbability(healthy, normal); val != 0.8 {
+		t.Error(
+			"Emission probability of 'normal' by 'Healty' has to be 0.8, but got", val,
+		)
+	}
+	if val, _ := v.GetTransitionProbability(healthy, fever); val != 2.0/3.0 {
+		t.Error(
+			"Transition probability from 'Healty' to 'Fever' has to be 2/3, but got", val,
+		)
+	}
+}
+
+func TestViterbiStatesObservations(t *testing.T) {
+	v, incStates, incomingObservations := healthModel()
+	states := v.States()
+	if len(states) != len(incStates) {
+		t.Fatal(
+			"Number of states has to be", len(incStates), ", but got", len(states),
+		)
+	}
+	for i := range incStates {
+		if states[i] != incStates[i] {
+			t.Error(
+				"State on position", i, "has to be", incStates[i], ", but got", states[i],
+			)
+		}
+	}
+	observations := v.Observations()
+	if len(observations) != len(incomingObservations) {
+		t.Fatal(
+			"Number of observations has to be", len(incomingObservations), ", but got", len(observations),
+		)
+	}
+	for i := range incomingObservations {
+		if observations[i] != incomingObservations[i] {
+			t.Error(
+				"Observation on position", i, "has to be", incomingObservations[i], ", but got", observations[i],
+			)
+		}
+	}
+
+	// Returned slices are copies
+	states[0] = incStates[1]
+	observations[0] = incomingObservations[2]
+	if v.states[0] != incStates[0] || v.observations[0] != incomingObservations[0] {
+		t.Error(
+			"Changing returned slices has not to affect the model",
+		)
+	}
+}
+
+func TestViterbiSingleObservation(t *testing.T) {
+	v, incStates, incomingObservations := healthModel()
+	healthy, fever := incStates[0], incStates[1]
+	v.observations = []Observation{incomingObservations[2]}
+
+	// 'Healthy': 0.6·0.1, 'Fever': 0.4·0.6
+	vpath, err := v.EvalPath()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if vpath.String() != "0.24: [2]" || len(vpath.StepProbabilities) != 1 || vpath.StepProbabilities[0] != 0.24 {
+		t.Error(
+			"Path has to be '0.24: [2]' with single step, but got", vpath, vpath.StepProbabilities,
+		)
+	}
+
+	// End probability flips the best state: 'Healthy': 0.06, 'Fever': 0.024
+	v.PutEndProbability(fever, 0.1)
+	logV := v.ToLog()
+	evaluators := map[string]func() (ViterbiPath, error){
+		"EvalPath":                  v.EvalPath,
+		"EvalPathCompact":           v.EvalPathCompact,
+		"EvalPath2":                 v.EvalPath2,
+		"EvalPathLogProbabilities":  logV.EvalPathLogProbabilities,
+		"EvalPath2LogProbabilities": logV.EvalPath2LogProbabilities,
+		"EvalPathParallel": func() (ViterbiPath, error) {
+			return v.EvalPathParallel(4)
+		},
+	}
+	for name, eval := range evaluators {
+		vpath, err := eval()
+		if err != nil {
+			t.Fatal(name, err)
+		}
+		if len(vpath.Path) != 1 || vpath.Path[0] != healthy || math.Abs(vpath.LogProbability-math.Log(0.06)) > 1e-12 {
+			t.Error(
+				name, "has to return path [1] with probability 0.06, but got", vpath,
+			)
+		}
+	}
+
+	if err := v.Begin(); err != nil {
+		t.Fatal(err)
+	}
+	if err := v.Step(incomingObservations[2]); err != nil {
+		t.Fatal(err)
+	}
+	if vpath, err := v.CurrentBest(); err != nil || vpath.Path[0] != healthy {
+		t.Error(
+			"Streamed path has to be [1], but got", vpath, err,
+		)
+	}
+}